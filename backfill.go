@@ -0,0 +1,415 @@
+package twigo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/arshamalh/twigo/entities"
+	"github.com/arshamalh/twigo/utils"
+)
+
+// Backfill pulls every Tweet matching a query between two times from the full-archive search.
+//
+// The time range is split into partitions with about the same number of Tweets,
+// estimated using GetAllTweetsCount, and the partitions are paginated in parallel under a shared rate limiter,
+// so the 1 request per second of the full-archive search is used without waiting for each response.
+//
+// With a CheckpointFile, the progress of every partition is saved after each page,
+// so an interrupted backfill continues from the last next_token of each partition,
+// remove the file to start over.
+//
+// Set the exported fields before calling Run.
+type Backfill struct {
+	Query     string
+	StartTime time.Time
+	EndTime   time.Time
+	// Params of the search requests, like "expansions" or "tweet.fields",
+	// "max_results" is 500 by default, the time range and pagination params are set by the backfill.
+	Params Map
+	// Number of partitions fetched at the same time, default is 4.
+	Workers int
+	// Approximate number of Tweets in each partition, default is 100000,
+	// there are at least as many partitions as Workers.
+	PartitionSize int
+	// Minimum time between two requests of all workers, default is 1 second.
+	RequestInterval time.Duration
+	// Path of the file to save the progress in, nothing is saved if it's empty.
+	CheckpointFile string
+
+	client     *Client
+	limiter    *interval_limiter
+	mu         sync.Mutex
+	checkpoint *backfill_checkpoint
+	// Held while calling the handler, so it's never called concurrently, without blocking Progress.
+	handler_mu sync.Mutex
+}
+
+// A time range of a Backfill.
+type BackfillPartition struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// Estimated number of Tweets in the partition.
+	Estimate int `json:"estimate"`
+	// Token of the next page, empty before the first page.
+	NextToken string `json:"next_token,omitempty"`
+	// ID of the oldest Tweet received, pages go back in time, so only older Tweets are new.
+	OldestID string `json:"oldest_id,omitempty"`
+	// Number of Tweets received so far, without duplicates.
+	Tweets int  `json:"tweets"`
+	Done   bool `json:"done"`
+}
+
+type backfill_checkpoint struct {
+	Query      string               `json:"query"`
+	StartTime  time.Time            `json:"start_time"`
+	EndTime    time.Time            `json:"end_time"`
+	Partitions []*BackfillPartition `json:"partitions"`
+}
+
+// Returns a Backfill of the Tweets matching query, from start_time up to end_time,
+// it needs the Academic Research access level.
+func (c *Client) FullArchiveBackfill(query string, start_time, end_time time.Time) *Backfill {
+	return &Backfill{
+		Query:     query,
+		StartTime: start_time,
+		EndTime:   end_time,
+		client:    c,
+	}
+}
+
+func (b *Backfill) set_defaults() {
+	if b.Workers <= 0 {
+		b.Workers = 4
+	}
+	if b.PartitionSize <= 0 {
+		b.PartitionSize = 100000
+	}
+	if b.RequestInterval <= 0 {
+		b.RequestInterval = time.Second
+	}
+	if b.limiter == nil {
+		b.limiter = &interval_limiter{interval: b.RequestInterval}
+	}
+}
+
+// Estimates the number of Tweets and splits the time range into partitions,
+// Run calls it if there is no checkpoint to continue from.
+func (b *Backfill) Plan(ctx context.Context) ([]*BackfillPartition, error) {
+	b.set_defaults()
+	if !b.StartTime.Before(b.EndTime) {
+		return nil, invalid_params("start_time must be before end_time")
+	}
+
+	buckets, err := b.estimate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(math.Ceil(float64(buckets.Total()) / float64(b.PartitionSize)))
+	if count < b.Workers {
+		count = b.Workers
+	}
+	return split_partitions(buckets, b.StartTime, b.EndTime, count), nil
+}
+
+// Counts the Tweets of every hour of the time range, paging under the rate limiter of the backfill.
+func (b *Backfill) estimate(ctx context.Context) (CountSeries, error) {
+	if err := b.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	counts, err := b.client.GetAllTweetsCountCtx(ctx, b.Query, &CountsOptions{
+		StartTime:   b.StartTime,
+		EndTime:     b.EndTime,
+		Granularity: GranularityHour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series := counts.Data
+	for counts.Meta.NextToken != "" {
+		if err := b.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		if counts, err = counts.NextPageCtx(ctx); err != nil {
+			return nil, err
+		}
+		series = append(series, counts.Data...)
+	}
+	// split_partitions walks the buckets in chronological order, pages go back in time.
+	series.sort_by_start()
+	return series, nil
+}
+
+// Splits the time range of the buckets into count partitions with about the same number of Tweets,
+// assuming Tweets are spread evenly inside each bucket, the partitions cover start up to end.
+func split_partitions(buckets CountSeries, start, end time.Time, count int) []*BackfillPartition {
+	total := buckets.Total()
+	if total == 0 {
+		return nil
+	}
+
+	target := float64(total) / float64(count)
+	cuts := []time.Time{start}
+	seen, next := 0.0, target
+	for _, bucket := range buckets {
+		for bucket.TweetCount > 0 && seen+float64(bucket.TweetCount) > next && len(cuts) < count {
+			fraction := (next - seen) / float64(bucket.TweetCount)
+			cut := bucket.Start.Add(time.Duration(fraction * float64(bucket.End.Sub(bucket.Start)))).Truncate(time.Second)
+			if cut.After(cuts[len(cuts)-1]) && cut.Before(end) {
+				cuts = append(cuts, cut)
+			}
+			next += target
+		}
+		seen += float64(bucket.TweetCount)
+	}
+	cuts = append(cuts, end)
+
+	partitions := []*BackfillPartition{}
+	for i := 0; i < len(cuts)-1; i++ {
+		partitions = append(partitions, &BackfillPartition{
+			StartTime: cuts[i],
+			EndTime:   cuts[i+1],
+			Estimate:  estimate_between(buckets, cuts[i], cuts[i+1]),
+		})
+	}
+	return partitions
+}
+
+// Estimated number of Tweets between from and to.
+func estimate_between(buckets CountSeries, from, to time.Time) int {
+	estimate := 0.0
+	for _, bucket := range buckets {
+		overlap_start, overlap_end := bucket.Start, bucket.End
+		if from.After(overlap_start) {
+			overlap_start = from
+		}
+		if to.Before(overlap_end) {
+			overlap_end = to
+		}
+		if overlap_end.After(overlap_start) {
+			estimate += float64(bucket.TweetCount) * float64(overlap_end.Sub(overlap_start)) / float64(bucket.End.Sub(bucket.Start))
+		}
+	}
+	return int(math.Round(estimate))
+}
+
+// Fetches all partitions and calls handler for every page,
+// Tweets a partition already received, even before a restart, are removed from the page.
+//
+// handler is never called concurrently, and the checkpoint is saved after it returns,
+// so returning an error stops the backfill before the page is marked as done.
+//
+// It returns nil when all partitions are done, or the first error that happened.
+func (b *Backfill) Run(ctx context.Context, handler func(page *TweetsResponse) error) error {
+	b.set_defaults()
+
+	checkpoint, err := b.load_checkpoint()
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		partitions, err := b.Plan(ctx)
+		if err != nil {
+			return err
+		}
+		checkpoint = &backfill_checkpoint{
+			Query:      b.Query,
+			StartTime:  b.StartTime,
+			EndTime:    b.EndTime,
+			Partitions: partitions,
+		}
+	}
+
+	b.mu.Lock()
+	b.checkpoint = checkpoint
+	err = b.save_checkpoint()
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	workers_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := make(chan *BackfillPartition)
+	errs := make(chan error, b.Workers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < b.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partition := range queue {
+				if err := b.fetch(workers_ctx, partition, handler); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, partition := range checkpoint.Partitions {
+		if partition.Done {
+			continue
+		}
+		select {
+		case queue <- partition:
+		case <-workers_ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// Paginates a partition until it's done.
+func (b *Backfill) fetch(ctx context.Context, partition *BackfillPartition, handler func(page *TweetsResponse) error) error {
+	for {
+		b.mu.Lock()
+		params := Map{"max_results": 500}
+		for key, value := range b.Params {
+			params[key] = value
+		}
+		params["start_time"] = partition.StartTime
+		params["end_time"] = partition.EndTime
+		delete(params, "pagination_token")
+		delete(params, "next_token")
+		if partition.NextToken != "" {
+			params["next_token"] = partition.NextToken
+		}
+		done := partition.Done
+		b.mu.Unlock()
+
+		if done {
+			return nil
+		}
+
+		if err := b.limiter.wait(ctx); err != nil {
+			return err
+		}
+		page, err := b.client.SearchAllTweetsCtx(ctx, b.Query, params)
+		if err != nil {
+			return err
+		}
+
+		// Only this worker changes the partition, so it can be read without b.mu.
+		oldest_id := partition.OldestID
+		tweets := make([]entities.Tweet, 0, len(page.Data))
+		for _, tweet := range page.Data {
+			if oldest_id == "" || utils.NewerID(oldest_id, tweet.ID) {
+				tweets = append(tweets, tweet)
+			}
+		}
+		page.Data = tweets
+		for _, tweet := range tweets {
+			if oldest_id == "" || utils.NewerID(oldest_id, tweet.ID) {
+				oldest_id = tweet.ID
+			}
+		}
+
+		b.handler_mu.Lock()
+		err = handler(page)
+		if err == nil {
+			b.mu.Lock()
+			partition.Tweets += len(tweets)
+			partition.NextToken = page.Meta.NextToken
+			partition.OldestID = oldest_id
+			partition.Done = page.Meta.NextToken == ""
+			err = b.save_checkpoint()
+			b.mu.Unlock()
+		}
+		b.handler_mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Returns the partitions and their progress, nil before Run is called.
+func (b *Backfill) Progress() []BackfillPartition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.checkpoint == nil {
+		return nil
+	}
+	partitions := make([]BackfillPartition, 0, len(b.checkpoint.Partitions))
+	for _, partition := range b.checkpoint.Partitions {
+		partitions = append(partitions, *partition)
+	}
+	return partitions
+}
+
+// Loads the checkpoint file, if there is one.
+func (b *Backfill) load_checkpoint() (*backfill_checkpoint, error) {
+	if b.CheckpointFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(b.CheckpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &backfill_checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("twigo: invalid checkpoint file %s: %w", b.CheckpointFile, err)
+	}
+	if checkpoint.Query != b.Query || !checkpoint.StartTime.Equal(b.StartTime) || !checkpoint.EndTime.Equal(b.EndTime) {
+		return nil, fmt.Errorf("twigo: checkpoint file %s belongs to another backfill", b.CheckpointFile)
+	}
+	return checkpoint, nil
+}
+
+// Saves the checkpoint, b.mu must be held.
+func (b *Backfill) save_checkpoint() error {
+	if b.CheckpointFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(b.checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(b.CheckpointFile, data, 0o644)
+}
+
+// Spaces requests of many goroutines at least interval apart.
+type interval_limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// Waits for the next free slot, or returns early if ctx is done.
+func (l *interval_limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(at))
+}
@@ -0,0 +1,75 @@
+package twigo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitPartitions(t *testing.T) {
+	start := time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+
+	tests := []struct {
+		name   string
+		series CountSeries
+		count  int
+		// Expected start times of the partitions, as offsets from start, and their estimates.
+		want_starts    []time.Duration
+		want_estimates []int
+	}{
+		{
+			name:   "no Tweets",
+			series: test_series(start, time.Hour, 0, 0, 0, 0),
+			count:  4,
+		},
+		{
+			name:           "one partition",
+			series:         test_series(start, time.Hour, 10, 20, 30, 40),
+			count:          1,
+			want_starts:    []time.Duration{0},
+			want_estimates: []int{100},
+		},
+		{
+			name:           "uniform counts",
+			series:         test_series(start, time.Hour, 100, 100, 100, 100),
+			count:          4,
+			want_starts:    []time.Duration{0, time.Hour, 2 * time.Hour, 3 * time.Hour},
+			want_estimates: []int{100, 100, 100, 100},
+		},
+		{
+			name:           "skewed counts",
+			series:         test_series(start, time.Hour, 0, 0, 0, 400),
+			count:          2,
+			want_starts:    []time.Duration{0, 3*time.Hour + 30*time.Minute},
+			want_estimates: []int{200, 200},
+		},
+		{
+			name:           "counts of a burst",
+			series:         test_series(start, time.Hour, 300, 100, 0, 0),
+			count:          2,
+			want_starts:    []time.Duration{0, 40 * time.Minute},
+			want_estimates: []int{200, 200},
+		},
+	}
+	for _, test := range tests {
+		partitions := split_partitions(test.series, start, end, test.count)
+		if len(partitions) != len(test.want_starts) {
+			t.Errorf("%s: got %d partitions, want %d", test.name, len(partitions), len(test.want_starts))
+			continue
+		}
+		for i, partition := range partitions {
+			want_start := start.Add(test.want_starts[i])
+			want_end := end
+			if i+1 < len(test.want_starts) {
+				want_end = start.Add(test.want_starts[i+1])
+			}
+			if !partition.StartTime.Equal(want_start) || !partition.EndTime.Equal(want_end) {
+				t.Errorf("%s: partition %d is %s - %s, want %s - %s",
+					test.name, i, partition.StartTime, partition.EndTime, want_start, want_end)
+			}
+			if partition.Estimate != test.want_estimates[i] {
+				t.Errorf("%s: partition %d estimate = %d, want %d", test.name, i, partition.Estimate, test.want_estimates[i])
+			}
+		}
+	}
+}
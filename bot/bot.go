@@ -0,0 +1,360 @@
+// Package bot answers the Tweets mentioning a user, like commands sent to a bot.
+//
+// A Bot polls the mentions of its user, or receives them from a filtered stream,
+// parses the command after its mention, routes it to the handler of that command,
+// and replies to the mention with what the handler returns:
+//
+//	b := bot.New(client)
+//	b.Use(bot.Cooldown(time.Minute))
+//	b.Handle("ping", func(ctx context.Context, mention *bot.Mention) (string, error) {
+//		return "pong", nil
+//	})
+//	err := b.Run(ctx)
+//
+// Every mention is answered at most once, even across restarts if CheckpointFile is set.
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arshamalh/twigo"
+	"github.com/arshamalh/twigo/utils"
+)
+
+// Number of answered mentions remembered, to recognize them if they are delivered again.
+const max_answered = 1000
+
+// Answers a mention, the returned text is replied to it, nothing is replied if it's empty.
+type Handler func(ctx context.Context, mention *Mention) (string, error)
+
+// Bot routes the mentions of its user to the handlers of their commands, and replies to them.
+//
+// Mentions are deduplicated by their Tweet ID, and the mentions of the bot itself and Retweets are ignored.
+// A mention is marked as answered before its handler is called,
+// so it's never answered twice, but a crash in the middle of a handler leaves it unanswered.
+//
+// Set the exported fields before calling Run or RunStream.
+type Bot struct {
+	// ID and username of the user the bot replies as, the authenticated user of the client by default.
+	UserID   string
+	UserName string
+	// Time between two polls of the mentions, default is 1 minute.
+	Interval time.Duration
+	// Params of the requests, "expansions", "tweet.fields" and "user.fields" by default,
+	// the author and the fields of the Tweets the bot needs are asked for.
+	Params twigo.Map
+	// On the first run, the bot only answers mentions posted after it started,
+	// with AnswerExisting, it answers the latest page of mentions too.
+	AnswerExisting bool
+	// Path of the file to save the newest mention and the answered ones in, nothing is saved if it's empty.
+	CheckpointFile string
+	// Called with the errors of handlers and replies, which don't stop the bot.
+	OnError func(mention *Mention, err error)
+
+	client      *twigo.Client
+	handlers    map[string]Handler
+	fallback    Handler
+	middlewares []Middleware
+
+	mu         sync.Mutex
+	checkpoint *bot_checkpoint
+	answered   map[string]bool
+}
+
+type bot_checkpoint struct {
+	UserID   string   `json:"user_id"`
+	SinceID  string   `json:"since_id"`
+	Answered []string `json:"answered"`
+}
+
+// Returns a Bot replying with client, which must have a user context.
+func New(client *twigo.Client) *Bot {
+	return &Bot{
+		client:   client,
+		handlers: map[string]Handler{},
+	}
+}
+
+// Routes the mentions with this command to handler, commands are case-insensitive.
+func (b *Bot) Handle(command string, handler Handler) {
+	b.handlers[strings.ToLower(command)] = handler
+}
+
+// Routes the mentions without a command, or with a command that has no handler, to handler,
+// they are ignored by default.
+func (b *Bot) HandleDefault(handler Handler) {
+	b.fallback = handler
+}
+
+// Adds middlewares to all handlers, the first one added runs first.
+func (b *Bot) Use(middlewares ...Middleware) {
+	b.middlewares = append(b.middlewares, middlewares...)
+}
+
+func (b *Bot) set_defaults() {
+	if b.Interval <= 0 {
+		b.Interval = time.Minute
+	}
+	params := make(twigo.Map, len(b.Params)+3)
+	for key, value := range b.Params {
+		params[key] = value
+	}
+	b.Params = params
+	b.Params["expansions"] = with_values(b.Params["expansions"], "author_id")
+	b.Params["tweet.fields"] = with_values(b.Params["tweet.fields"], "author_id", "conversation_id", "created_at", "referenced_tweets")
+	b.Params["user.fields"] = with_values(b.Params["user.fields"], "username")
+}
+
+// Loads the user of the bot and the checkpoint.
+func (b *Bot) start(ctx context.Context) error {
+	b.set_defaults()
+
+	if b.UserID == "" || b.UserName == "" {
+		me, err := b.client.GetMeCtx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		b.UserID, b.UserName = me.Data.ID, me.Data.UserName
+	}
+
+	checkpoint, err := b.load_checkpoint()
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.checkpoint = checkpoint
+	b.answered = make(map[string]bool, len(checkpoint.Answered))
+	for _, tweet_id := range checkpoint.Answered {
+		b.answered[tweet_id] = true
+	}
+	return nil
+}
+
+// Polls the mentions of the bot every Interval, and answers them, the oldest first, until ctx is done.
+//
+// It returns nil when ctx is done, or the first error of a poll or of saving the checkpoint,
+// errors of handlers and replies are passed to OnError instead.
+func (b *Bot) Run(ctx context.Context) error {
+	if err := b.start(ctx); err != nil {
+		return err
+	}
+
+	watcher := b.client.WatchUserMentions(b.UserID)
+	watcher.Params = b.Params
+	watcher.SinceID = b.checkpoint.SinceID
+	watcher.SkipExisting = !b.AnswerExisting
+
+	ticker := time.NewTicker(b.Interval)
+	defer ticker.Stop()
+	for {
+		err := watcher.Poll(ctx, func(tweet *twigo.TimelineTweet) error {
+			return b.answer(ctx, tweet.Hydrate())
+		})
+		if err == nil {
+			err = b.advance(watcher.SinceID)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Answers the mentions delivered by stream, which should have a rule matching them, like "@ourbot -is:retweet",
+// the Params of the bot are used if stream has none.
+//
+// It returns when stream stops, or with the first error of saving the checkpoint.
+func (b *Bot) RunStream(ctx context.Context, stream *twigo.Stream) error {
+	if err := b.start(ctx); err != nil {
+		return err
+	}
+	if stream.Params == nil {
+		stream.Params = b.Params
+	}
+
+	stream_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The first error is kept, Tweets delivered after it are not answered, since the checkpoint can't be trusted.
+	var answer_err error
+	err := stream.Run(stream_ctx, func(tweet *twigo.StreamTweet) {
+		if answer_err != nil {
+			return
+		}
+		err := b.answer(stream_ctx, tweet.Hydrate())
+		if err == nil {
+			err = b.advance(tweet.Data.ID)
+		}
+		if err != nil {
+			answer_err = err
+			cancel()
+		}
+	})
+	if answer_err != nil {
+		return answer_err
+	}
+	return err
+}
+
+// Routes a mention to its handler, and replies to it.
+// Only errors of saving the checkpoint are returned, the bot can't keep its promise without it.
+func (b *Bot) answer(ctx context.Context, tweet twigo.HydratedTweet) error {
+	if tweet.AuthorID == b.UserID || is_retweet(tweet) {
+		return nil
+	}
+
+	b.mu.Lock()
+	if b.answered[tweet.ID] {
+		b.mu.Unlock()
+		return nil
+	}
+	b.answered[tweet.ID] = true
+	b.checkpoint.Answered = append(b.checkpoint.Answered, tweet.ID)
+	if len(b.checkpoint.Answered) > max_answered {
+		delete(b.answered, b.checkpoint.Answered[0])
+		b.checkpoint.Answered = b.checkpoint.Answered[1:]
+	}
+	err := b.save_checkpoint()
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	mention := parse_mention(tweet, b.UserName)
+	handler, ok := b.handlers[mention.Command]
+	if !ok {
+		handler = b.fallback
+	}
+	if handler == nil {
+		return nil
+	}
+	for i := len(b.middlewares) - 1; i >= 0; i-- {
+		handler = b.middlewares[i](handler)
+	}
+
+	reply, err := handler(ctx, mention)
+	if err != nil {
+		b.report(mention, err)
+		return nil
+	}
+	if reply == "" {
+		return nil
+	}
+
+	_, err = b.client.CreateTweetCtx(ctx, reply, &twigo.CreateTweetOptions{
+		Reply: &twigo.CreateTweetReply{InReplyToTweetID: tweet.ID},
+	})
+	if err != nil {
+		b.report(mention, fmt.Errorf("twigo/bot: replying to %s: %w", tweet.ID, err))
+	}
+	return nil
+}
+
+func (b *Bot) report(mention *Mention, err error) {
+	if b.OnError != nil {
+		b.OnError(mention, err)
+	}
+}
+
+// Saves since_id as the newest mention, if it's newer than the saved one.
+func (b *Bot) advance(since_id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !utils.NewerID(since_id, b.checkpoint.SinceID) {
+		return nil
+	}
+	b.checkpoint.SinceID = since_id
+	return b.save_checkpoint()
+}
+
+func is_retweet(tweet twigo.HydratedTweet) bool {
+	for _, referenced := range tweet.ReferencedTweets {
+		if referenced.Type == "retweeted" {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds values to a comma separated list or a slice of params, if they aren't in it.
+func with_values(param interface{}, values ...string) []string {
+	var list []string
+	switch param := param.(type) {
+	case string:
+		if param != "" {
+			list = strings.Split(param, ",")
+		}
+	case []string:
+		list = append(list, param...)
+	}
+
+	for _, value := range values {
+		found := false
+		for _, item := range list {
+			if item == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// Loads the checkpoint file, an empty checkpoint is returned if there is none.
+func (b *Bot) load_checkpoint() (*bot_checkpoint, error) {
+	empty := &bot_checkpoint{UserID: b.UserID}
+	if b.CheckpointFile == "" {
+		return empty, nil
+	}
+
+	data, err := os.ReadFile(b.CheckpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return empty, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &bot_checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("twigo/bot: invalid checkpoint file %s: %w", b.CheckpointFile, err)
+	}
+	if checkpoint.UserID != b.UserID {
+		return nil, fmt.Errorf("twigo/bot: checkpoint file %s belongs to another user", b.CheckpointFile)
+	}
+	return checkpoint, nil
+}
+
+// Saves the checkpoint, b.mu must be held.
+func (b *Bot) save_checkpoint() error {
+	if b.CheckpointFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(b.checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(b.CheckpointFile, data, 0o644)
+}
@@ -0,0 +1,324 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arshamalh/twigo"
+	"github.com/arshamalh/twigo/entities"
+	"github.com/arshamalh/twigo/utils"
+)
+
+// The API of the bot user "OurBot" with the ID 42, its mentions are filtered by since_id,
+// and its replies are recorded.
+type fake_bot_api struct {
+	mu sync.Mutex
+	// Mentions of the bot, the newest first.
+	mentions []twigo.Map
+	// Bodies of the created Tweets.
+	replies []string
+	polls   int
+	// Called after a poll of the mentions is answered, with the number of polls so far.
+	on_poll func(polls int)
+	// Called after a reply is created, with the number of replies so far.
+	on_reply func(replies int)
+}
+
+func (f *fake_bot_api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/2/users/me":
+		w.Write([]byte(`{"data":{"id":"42","name":"Bot","username":"OurBot"}}`))
+	case r.Method == "POST" && r.URL.Path == "/2/tweets":
+		body, _ := io.ReadAll(r.Body)
+		f.replies = append(f.replies, string(body))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"data":{"id":"%d","text":"reply"}}`, 1000+len(f.replies))
+		if f.on_reply != nil {
+			f.on_reply(len(f.replies))
+		}
+	case r.URL.Path == "/2/users/42/mentions":
+		since_id := r.URL.Query().Get("since_id")
+		data := []twigo.Map{}
+		for _, mention := range f.mentions {
+			if since_id == "" || utils.NewerID(mention["id"].(string), since_id) {
+				data = append(data, mention)
+			}
+		}
+		meta := twigo.Map{"result_count": len(data)}
+		if len(data) != 0 {
+			meta["newest_id"] = data[0]["id"]
+		}
+		users := []twigo.Map{{"id": "7", "username": "alice"}, {"id": "8", "username": "bob"}}
+		json.NewEncoder(w).Encode(twigo.Map{"data": data, "meta": meta, "includes": twigo.Map{"users": users}})
+		f.polls++
+		if f.on_poll != nil {
+			f.on_poll(f.polls)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fake_bot_api) mention(id, author_id, text string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mentions = append([]twigo.Map{{"id": id, "author_id": author_id, "text": text}}, f.mentions...)
+}
+
+func new_test_bot(t *testing.T) (*Bot, *fake_bot_api) {
+	api := &fake_bot_api{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	client, _ := twigo.NewClient(
+		&twigo.Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret"},
+		twigo.WithBaseURL(server.URL),
+	)
+	b := New(client)
+	b.Interval = time.Millisecond
+	return b, api
+}
+
+// Runs the bot for the given number of polls.
+func run_polls(t *testing.T, b *Bot, api *fake_bot_api, polls int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	api.mu.Lock()
+	api.polls = 0
+	// The poll after the last one is canceled, so the last one is fully answered.
+	api.on_poll = func(n int) {
+		if n > polls {
+			cancel()
+		}
+	}
+	api.mu.Unlock()
+
+	if err := b.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Fatal("the bot didn't poll its mentions")
+	}
+}
+
+// Returns the replied Tweet IDs and texts of the replies, like "102: pong".
+func replies_of(t *testing.T, api *fake_bot_api) []string {
+	replies := []string{}
+	for _, body := range api.replies {
+		reply := struct {
+			Text  string
+			Reply struct {
+				InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+			}
+		}{}
+		if err := json.Unmarshal([]byte(body), &reply); err != nil {
+			t.Fatal(err)
+		}
+		replies = append(replies, reply.Reply.InReplyToTweetID+": "+reply.Text)
+	}
+	return replies
+}
+
+func TestParseMention(t *testing.T) {
+	tests := []struct {
+		text string
+		// Expected command, args and text.
+		command string
+		args    []string
+		rest    string
+	}{
+		{"@OurBot ping", "ping", []string{}, ""},
+		{"@alice @OurBot, /Remind me in 2h &amp; more", "remind", []string{"me", "in", "2h", "&", "more"}, "me in 2h & more"},
+		{"hey @ourbot help!", "help", []string{}, ""},
+		{"@OurBot: !Status now", "status", []string{"now"}, "now"},
+		{"@ourbot @alice", "", nil, ""},
+		{"@OurBot", "", nil, ""},
+	}
+	for _, test := range tests {
+		mention := parse_mention(twigo.HydratedTweet{Tweet: entities.Tweet{Text: test.text}}, "OurBot")
+		if mention.Command != test.command || fmt.Sprint(mention.Args) != fmt.Sprint(test.args) || mention.Text != test.rest {
+			t.Errorf("%q: command %q, args %q, text %q, want %q, %q, %q", test.text, mention.Command, mention.Args, mention.Text, test.command, test.args, test.rest)
+		}
+	}
+}
+
+func TestBotRouting(t *testing.T) {
+	b, api := new_test_bot(t)
+	b.AnswerExisting = true
+	b.Handle("Ping", func(ctx context.Context, mention *Mention) (string, error) {
+		return "@" + mention.Tweet.Author.UserName + " pong " + mention.Text, nil
+	})
+	b.Handle("fail", func(ctx context.Context, mention *Mention) (string, error) {
+		return "", errors.New("handler failed")
+	})
+	b.Handle("quiet", func(ctx context.Context, mention *Mention) (string, error) {
+		return "", nil
+	})
+	b.HandleDefault(func(ctx context.Context, mention *Mention) (string, error) {
+		return fmt.Sprintf("unknown command %q", mention.Command), nil
+	})
+	reported := []string{}
+	b.OnError = func(mention *Mention, err error) {
+		reported = append(reported, mention.Tweet.ID+": "+err.Error())
+	}
+
+	api.mention("101", "7", "@OurBot ping")
+	api.mention("102", "8", "@OurBot PING loudly")
+	api.mention("103", "7", "@OurBot dance")
+	api.mention("104", "42", "@OurBot ping myself")
+	api.mention("105", "8", "@OurBot fail")
+	api.mention("106", "8", "@OurBot quiet")
+	api.mentions[0]["text"] = "RT @alice: @OurBot ping"
+	api.mentions[0]["referenced_tweets"] = []twigo.Map{{"type": "retweeted", "id": "101"}}
+	run_polls(t, b, api, 1)
+
+	// The bot's own mentions and Retweets are ignored.
+	want := []string{"101: @alice pong ", "102: @bob pong loudly", `103: unknown command "dance"`}
+	if got := replies_of(t, api); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("replies =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if fmt.Sprint(reported) != "[105: handler failed]" {
+		t.Errorf("reported errors = %v, want the one of 105", reported)
+	}
+}
+
+func TestBotMiddlewares(t *testing.T) {
+	b, api := new_test_bot(t)
+	b.AnswerExisting = true
+	order := []string{}
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, mention *Mention) (string, error) {
+				order = append(order, name)
+				return next(ctx, mention)
+			}
+		}
+	}
+	b.Use(trace("first"), Allowlist("7", "8"), Cooldown(time.Hour), trace("last"))
+	b.Handle("ping", func(ctx context.Context, mention *Mention) (string, error) {
+		return "pong", nil
+	})
+
+	api.mention("101", "7", "@OurBot ping")
+	api.mention("102", "9", "@OurBot ping")
+	api.mention("103", "7", "@OurBot ping")
+	api.mention("104", "8", "@OurBot ping")
+	run_polls(t, b, api, 1)
+
+	// 102 isn't allowed, and 103 is in the cooldown of its author.
+	if got := replies_of(t, api); fmt.Sprint(got) != "[101: pong 104: pong]" {
+		t.Errorf("replies = %v, want the ones of 101 and 104", got)
+	}
+	if fmt.Sprint(order) != "[first last first first first last]" {
+		t.Errorf("middlewares ran as %v, want the first one added to run first", order)
+	}
+}
+
+func TestBotCheckpoint(t *testing.T) {
+	b, api := new_test_bot(t)
+	checkpoint := filepath.Join(t.TempDir(), "bot.json")
+	new_bot := func() *Bot {
+		restarted := New(b.client)
+		restarted.Interval = time.Millisecond
+		restarted.CheckpointFile = checkpoint
+		restarted.Handle("ping", func(ctx context.Context, mention *Mention) (string, error) {
+			return "pong", nil
+		})
+		return restarted
+	}
+
+	// The mentions before the first run are not answered.
+	api.mention("100", "7", "@OurBot ping")
+	run_polls(t, new_bot(), api, 1)
+	if len(api.replies) != 0 {
+		t.Errorf("replies = %v, want none for the existing mentions", api.replies)
+	}
+
+	api.mention("101", "7", "@OurBot ping")
+	api.mention("102", "8", "@OurBot ping")
+	run_polls(t, new_bot(), api, 2)
+	if got := replies_of(t, api); fmt.Sprint(got) != "[101: pong 102: pong]" {
+		t.Errorf("replies = %v, want the ones of 101 and 102", got)
+	}
+
+	data, _ := os.ReadFile(checkpoint)
+	saved := bot_checkpoint{}
+	json.Unmarshal(data, &saved)
+	if saved.UserID != "42" || saved.SinceID != "102" || fmt.Sprint(saved.Answered) != "[101 102]" {
+		t.Errorf("checkpoint = %s, want since_id 102 with 101 and 102 answered", data)
+	}
+
+	// A restart doesn't answer them again.
+	run_polls(t, new_bot(), api, 1)
+	if len(api.replies) != 2 {
+		t.Errorf("%d replies, want the 2 earlier ones", len(api.replies))
+	}
+
+	another := new_bot()
+	another.UserID, another.UserName = "43", "AnotherBot"
+	if err := another.Run(context.Background()); err == nil {
+		t.Error("no error for the checkpoint of another user")
+	}
+}
+
+func TestBotRunStream(t *testing.T) {
+	b, api := new_test_bot(t)
+	b.Handle("ping", func(ctx context.Context, mention *Mention) (string, error) {
+		return "pong", nil
+	})
+
+	// The stream is served by its own handler, the rest by the API of the bot.
+	mux := http.NewServeMux()
+	mux.Handle("/", api)
+	mux.HandleFunc("/2/tweets/search/stream", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Query().Get("expansions"), "author_id") {
+			t.Errorf("stream params = %s, want the ones of the bot", r.URL.RawQuery)
+		}
+		for _, line := range []string{
+			`{"data":{"id":"101","author_id":"7","text":"@OurBot ping"}}`,
+			`{"data":{"id":"102","author_id":"42","text":"@OurBot ping"}}`,
+			`{"data":{"id":"101","author_id":"7","text":"@OurBot ping"}}`,
+			`{"data":{"id":"103","author_id":"8","text":"@OurBot ping"}}`,
+		} {
+			fmt.Fprint(w, line+"\r\n")
+			w.(http.Flusher).Flush()
+		}
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client, _ := twigo.NewClient(
+		&twigo.Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret", BearerToken: "bearer"},
+		twigo.WithBaseURL(server.URL),
+	)
+	b.client = client
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	api.on_reply = func(replies int) {
+		if replies == 2 {
+			cancel()
+		}
+	}
+	if err := b.RunStream(ctx, client.FilteredStream(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The repeated mention is answered once.
+	if got := replies_of(t, api); fmt.Sprint(got) != "[101: pong 103: pong]" {
+		t.Errorf("replies = %v, want the ones of 101 and 103", got)
+	}
+}
@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"html"
+	"strings"
+
+	"github.com/arshamalh/twigo"
+)
+
+// A Tweet mentioning the bot, with the command parsed out of its text.
+//
+// For "@alice @ourbot remind me in 2h", Command is "remind", Args is ["me", "in", "2h"] and Text is "me in 2h".
+type Mention struct {
+	// The Tweet, with its author if it's expanded, which the bot does by default.
+	Tweet twigo.HydratedTweet
+	// First word after the mention of the bot, in lower case, without a leading "/" or "!",
+	// empty if the Tweet has nothing after the mentions.
+	Command string
+	// Words after the command.
+	Args []string
+	// Text after the command.
+	Text string
+}
+
+// Parses the command of a Tweet mentioning username, the mentions before the command are skipped,
+// like the ones Twitter adds to the beginning of replies.
+func parse_mention(tweet twigo.HydratedTweet, username string) *Mention {
+	mention := &Mention{Tweet: tweet}
+
+	// Texts of API v2 escape "&", "<" and ">" like HTML does.
+	words := strings.Fields(html.UnescapeString(tweet.Text))
+	start := 0
+	for i, word := range words {
+		if strings.EqualFold(strings.TrimRight(word, ",:"), "@"+username) {
+			start = i + 1
+			break
+		}
+	}
+	for start < len(words) && strings.HasPrefix(words[start], "@") {
+		start++
+	}
+	if start == len(words) {
+		return mention
+	}
+
+	mention.Command = strings.ToLower(strings.TrimRight(strings.TrimLeft(words[start], "/!"), ",.:;!?"))
+	mention.Args = words[start+1:]
+	mention.Text = strings.Join(mention.Args, " ")
+	return mention
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Wraps a Handler, to run something before or after it, or to skip it,
+// a middleware skips a mention by returning an empty reply without calling next.
+type Middleware func(next Handler) Handler
+
+// Only answers the mentions of users with these IDs, others are ignored.
+func Allowlist(user_ids ...string) Middleware {
+	allowed := make(map[string]bool, len(user_ids))
+	for _, user_id := range user_ids {
+		allowed[user_id] = true
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, mention *Mention) (string, error) {
+			if !allowed[mention.Tweet.AuthorID] {
+				return "", nil
+			}
+			return next(ctx, mention)
+		}
+	}
+}
+
+// Answers at most one mention of each user per period, the mentions in between are ignored.
+// The times are kept in memory, so a restart forgets them.
+func Cooldown(period time.Duration) Middleware {
+	mu := sync.Mutex{}
+	last_answers := map[string]time.Time{}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, mention *Mention) (string, error) {
+			now := time.Now()
+			mu.Lock()
+			if last_answer, ok := last_answers[mention.Tweet.AuthorID]; ok && now.Sub(last_answer) < period {
+				mu.Unlock()
+				return "", nil
+			}
+			last_answers[mention.Tweet.AuthorID] = now
+			// Users out of their cooldown don't need to be remembered anymore.
+			if len(last_answers) > 1000 {
+				for user_id, last_answer := range last_answers {
+					if now.Sub(last_answer) >= period {
+						delete(last_answers, user_id)
+					}
+				}
+			}
+			mu.Unlock()
+
+			return next(ctx, mention)
+		}
+	}
+}
@@ -2,27 +2,44 @@ package twigo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/arshamalh/twigo/entities"
+	"github.com/arshamalh/twigo/query"
 	"github.com/arshamalh/twigo/utils"
 )
 
 const (
-	base_route = "https://api.twitter.com/2/"
+	default_base_url = "https://api.twitter.com/"
+	v2_prefix        = "2/"
 )
 
 type Client struct {
 	authorizedClient  *http.Client
+	httpClient        *http.Client
+	baseURL           string
+	userAgent         string
+	timeout           time.Duration
+	retryPolicy       *RetryPolicy
+	rateLimiter       *rate_limiter
+	observer          func(err error)
+	uploadURL         string
+	accessLevel       *query.AccessLevel
 	consumerKey       string
 	consumerSecret    string
 	accessToken       string
 	accessTokenSecret string
 	bearerToken       string
-	read_only_access  bool
+	tokenSource       TokenSource
+	userMu            sync.Mutex
 	userID            string
 	oauth_type        OAuthType
 }
@@ -30,88 +47,204 @@ type Client struct {
 type Map map[string]interface{}
 
 // ** Requests ** //
-func (c *Client) request(method, route string, params Map) (*http.Response, error) {
-	// OAuth_1a is always true for post and put routes
-	dataPayload, err := json.Marshal(params)
+
+// Sends a request to the endpoint of the registry named name,
+// path_params fill the placeholders of its path in order, like the user ID of "users/:id/tweets".
+//
+// Params are sent in the query string or the JSON body, as the endpoint describes them,
+// params the endpoint doesn't accept are rejected before sending.
+func (c *Client) call(ctx context.Context, name string, params Map, path_params ...string) (*http.Response, error) {
+	endpoint := find_endpoint(name)
+	if endpoint == nil {
+		return nil, fmt.Errorf("twigo: unknown endpoint %s", name)
+	}
+
+	oauth_type, err := c.auth_for(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-
-	request, err := http.NewRequest(method, base_route+route, bytes.NewBuffer(dataPayload))
+	route, err := endpoint.route(path_params)
+	if err != nil {
+		return nil, err
+	}
+	query_params, body, err := endpoint.split_params(params)
 	if err != nil {
 		return nil, err
 	}
 
-	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	full_route := c.baseURL + v2_prefix + route
+	raw_query, err := utils.QueryBuilder(query_params, endpoint.Query)
+	if err != nil {
+		return nil, invalid_params("%v", err)
+	}
+	if raw_query != "" {
+		full_route += "?" + raw_query
+	}
 
-	response, err := c.authorizedClient.Do(request)
+	var payload io.Reader
+	if endpoint.Method == "POST" || endpoint.Method == "PUT" {
+		dataPayload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewBuffer(dataPayload)
+	}
 
-	return response, err
+	// The chosen auth type is kept in the context of the request, for the rate limiter.
+	request, err := c.new_request(ContextWithOAuth(ctx, oauth_type), endpoint.Method, full_route, payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	}
+
+	http_client, err := c.authorize(request, oauth_type)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(http_client, request)
 }
 
-// Sends a get request with specified params
-//
-// oauth_1a ==> Whether or not to use OAuth 1.0a User context
-func (c *Client) get_request(route string, oauth_type OAuthType, params Map, endpoint_parameters []string) (*http.Response, error) {
-	parsedRoute, err := url.Parse(route)
+// Same as call, but the first path param is the ID of the authenticated user, like in "users/:id/likes".
+func (c *Client) call_as_user(ctx context.Context, name string, params Map, path_params ...string) (*http.Response, error) {
+	user_id, err := c.authenticated_user_id(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return c.call(ctx, name, params, append([]string{user_id}, path_params...)...)
+}
 
-	parsedRoute.RawQuery = utils.QueryMaker(params, endpoint_parameters)
-	fullRoute := base_route + parsedRoute.String()
+// Picks the auth type of a request to the endpoint.
+//
+// The one set by ContextWithOAuth must be supported by the endpoint and configured on the client,
+// otherwise the one set by SetOAuth, or the default one of the endpoint is used,
+// falling back to any other auth type of the endpoint the client has credentials for.
+func (c *Client) auth_for(ctx context.Context, endpoint *Endpoint) (OAuthType, error) {
+	if oauth_type, ok := oauth_from_context(ctx); ok {
+		if !endpoint.Supports(oauth_type) {
+			return 0, fmt.Errorf("%w: %s doesn't support %s", ErrUnsupportedOAuth, endpoint.Name, oauth_type)
+		}
+		if !c.has_credentials(oauth_type) {
+			return 0, fmt.Errorf("%w: %s for %s", ErrNoCredentials, oauth_type, endpoint.Name)
+		}
+		return oauth_type, nil
+	}
+
+	preferred := endpoint.DefaultAuth
+	if c.oauth_type != OAuth_Default && endpoint.Supports(c.oauth_type) {
+		preferred = c.oauth_type
+	}
+	if c.has_credentials(preferred) {
+		return preferred, nil
+	}
+	for _, oauth_type := range endpoint.Auth {
+		if c.has_credentials(oauth_type) {
+			return oauth_type, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s needs one of %v", ErrNoCredentials, endpoint.Name, endpoint.Auth)
+}
+
+// Whether or not the client is configured to send requests with the auth type.
+func (c *Client) has_credentials(oauth_type OAuthType) bool {
+	switch oauth_type {
+	case OAuth_1a:
+		return c.authorizedClient != nil
+	case OAuth_2:
+		return c.bearerToken != ""
+	case OAuth_2_User:
+		return c.tokenSource != nil
+	}
+	return false
+}
+
+// Parses the query, so malformed ones are rejected before they are sent,
+// and checks it against the access level set by WithAccessLevel, if any.
+//
+// full_archive is for the endpoints which are only available to the Academic Research access level.
+func (c *Client) check_query(search_query string, full_archive bool) error {
+	if c.accessLevel == nil {
+		_, err := query.Parse(search_query)
+		return err
+	}
+	if full_archive && *c.accessLevel < query.Academic {
+		return fmt.Errorf("%w: full-archive endpoints need %s access, not %s", query.ErrNotAvailable, query.Academic, *c.accessLevel)
+	}
+	// The query is sent as it's written, so its own length is checked.
+	return query.ValidateString(search_query, *c.accessLevel)
+}
 
-	if oauth_type == OAuth_1a {
-		//%% TODO: Should we define authorizedClient here? or tweepy is doing it wrong?
-		return c.authorizedClient.Get(fullRoute)
-	} else {
-		request, err := http.NewRequest("GET", fullRoute, nil)
+// Returns the http client that should send the request,
+// the OAuth 1.0a client signs user context requests, the others carry a bearer token,
+// the one of the app, or the access token of the OAuth 2.0 user.
+func (c *Client) authorize(request *http.Request, oauth_type OAuthType) (*http.Client, error) {
+	switch oauth_type {
+	case OAuth_1a:
+		return c.authorizedClient, nil
+	case OAuth_2_User:
+		token, err := c.tokenSource.Token(request.Context())
 		if err != nil {
 			return nil, err
 		}
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+		return c.httpClient, nil
+	}
 
-		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
-		client := http.Client{}
-		resp, err := client.Do(request)
-		if resp.StatusCode != 200 {
-			err := SpecialError{}
-			json.NewDecoder(resp.Body).Decode(&err)
-			defer resp.Body.Close()
-			return nil, err.Error()
-		}
-		return resp, err
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
+	return c.httpClient, nil
+}
+
+// Returns the auth type a request is sent with, as recorded in its context by call,
+// otherwise requests with a bearer token are taken as app-only ones, and the others as OAuth 1.0a ones.
+func request_oauth_type(request *http.Request) OAuthType {
+	if oauth_type, ok := oauth_from_context(request.Context()); ok {
+		return oauth_type
 	}
+	if strings.HasPrefix(request.Header.Get("Authorization"), "Bearer ") {
+		return OAuth_2
+	}
+	return OAuth_1a
+}
+
+// Returns the route of a request to the API, like "users/2244994945/tweets".
+func (c *Client) route_of(request *http.Request) string {
+	base_path := "/"
+	if base_url, err := url.Parse(c.baseURL); err == nil && base_url.Path != "" {
+		base_path = base_url.Path
+	}
+	return strings.TrimPrefix(request.URL.Path, base_path+v2_prefix)
 }
 
-func (c *Client) delete_request(route string) (*http.Response, error) {
-	// OAuth_1a is always true for delete routes
-	request, err := http.NewRequest("DELETE", base_route+route, nil)
+// Makes a new http request bound to ctx and carrying the client's user agent.
+func (c *Client) new_request(ctx context.Context, method, full_route string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, full_route, body)
 	if err != nil {
 		return nil, err
 	}
-	return c.authorizedClient.Do(request)
+
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return request, nil
 }
 
+// Makes every call prefer the auth type, when its endpoint supports it and the client has credentials for it.
+//
+// Deprecated: It changes the client for all goroutines, use ContextWithOAuth to choose the auth type of a single call,
+// every call picks the right one of its endpoint by default.
 func (c *Client) SetOAuth(oauth_type OAuthType) *Client {
-	if c.read_only_access {
-		oauth_type = OAuth_2
-	}
-	if c.bearerToken == "" {
-		oauth_type = OAuth_1a
-	}
 	c.oauth_type = oauth_type
 	return c
 }
 
-// TODO: Integrate this to get_request, for each request,
-// see if the oauth method is default, set it!, but we need the caller function?
-// or maybe we can call this function inside each method
+// Undoes SetOAuth, so every call uses the default auth type of its endpoint again.
+//
+// Deprecated: Calls already use the default auth type of their endpoints, see LookupEndpoint,
+// caller is ignored.
 func (c *Client) SetDefaultOAuth(caller string) *Client {
-	if doa, ok := DefaultOAuthes[caller]; ok {
-		c.oauth_type = doa
-	} else {
-		c.oauth_type = OAuth_2
-	}
+	c.oauth_type = OAuth_Default
 	return c
 }
 
@@ -123,50 +256,35 @@ func (c *Client) SetDefaultOAuth(caller string) *Client {
 //
 // text: Text of the Tweet being created. this field is required if media.media_ids is not present, otherwise pass empty string.
 //
-// params: A map of parameters.
-// you can pass some extra parameters, such as:
+// params: Either a *CreateTweetOptions, which is validated before sending, or a Map of the raw body parameters:
 // 	"direct_message_deep_link", "for_super_followers_only", "media", "geo", "poll", "reply", "reply_settings", "quote_tweet_id",
-// Some of these parameters are a little special and should be passed like this:
-// 	media := map[string][]string{
-// 		"media_ids": []string{}
-// 		"tagged_user_ids": []string{}
-// 	}
-// 	poll := twigo.Map{
-// 		"options": map[string]string{},
-// 		"duration_minutes": int value,
-// 	}
-// 	reply := twigo.Map{
-// 		"in_reply_to_tweet_id": "",
-// 		"exclude_reply_user_ids": []string{},
-// 	}
-// 	geo := map[string]string{"place_id": value}
-// 	params := twigo.Map{
-// 		"text": text
-// 		"media": media,
-// 		"geo": geo,
-// 		"poll": poll,
-// 		"reply": reply,
-// 	}
+// For example:
+// 	client.CreateTweet("Which one?", &twigo.CreateTweetOptions{
+// 		Poll: &twigo.CreateTweetPoll{Options: []string{"Go", "Rust"}, DurationMinutes: 60},
+// 		Reply: &twigo.CreateTweetReply{InReplyToTweetID: tweet_id},
+// 	})
 //
 // Reference
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/manage-tweets/api-reference/post-tweets
-func (c *Client) CreateTweet(text string, params Map) (*TweetResponse, error) {
-	if params == nil {
-		params = make(Map)
+func (c *Client) CreateTweet(text string, params Parameters) (*TweetResponse, error) {
+	return c.CreateTweetCtx(context.Background(), text, params)
+}
+
+// Same as CreateTweet, but the request is bound to ctx.
+func (c *Client) CreateTweetCtx(ctx context.Context, text string, options Parameters) (*TweetResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
 	if text != "" {
 		params["text"] = text
 	} else if params["media"] == nil {
-		return nil, fmt.Errorf("text or media is required")
+		return nil, invalid_params("text or media is required")
 	}
 
-	response, err := c.request(
-		"POST",
-		"tweets",
-		params,
-	)
+	response, err := c.call(ctx, "CreateTweet", params)
 
 	if err != nil {
 		return nil, err
@@ -185,9 +303,13 @@ func (c *Client) CreateTweet(text string, params Map) (*TweetResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/manage-tweets/api-reference/delete-tweets-id
 func (c *Client) DeleteTweet(tweet_id string) (*DeleteResponse, error) {
-	route := fmt.Sprintf("tweets/%s", tweet_id)
+	return c.DeleteTweetCtx(context.Background(), tweet_id)
+}
+
+// Same as DeleteTweet, but the request is bound to ctx.
+func (c *Client) DeleteTweetCtx(ctx context.Context, tweet_id string) (*DeleteResponse, error) {
 
-	response, err := c.delete_request(route)
+	response, err := c.call(ctx, "DeleteTweet", nil, tweet_id)
 
 	if err != nil {
 		return nil, err
@@ -208,17 +330,16 @@ func (c *Client) DeleteTweet(tweet_id string) (*DeleteResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/post-users-id-likesx
 func (c *Client) Like(tweet_id string) (*LikeResponse, error) {
+	return c.LikeCtx(context.Background(), tweet_id)
+}
+
+// Same as Like, but the request is bound to ctx.
+func (c *Client) LikeCtx(ctx context.Context, tweet_id string) (*LikeResponse, error) {
 	data := Map{
 		"tweet_id": tweet_id,
 	}
 
-	route := fmt.Sprintf("users/%s/likes", c.userID)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "Like", data)
 
 	if err != nil {
 		return nil, err
@@ -240,9 +361,13 @@ func (c *Client) Like(tweet_id string) (*LikeResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/delete-users-id-likes-tweet_id
 func (c *Client) Unlike(tweet_id string) (*LikeResponse, error) {
-	route := fmt.Sprintf("users/%s/likes/%s", c.userID, tweet_id)
+	return c.UnlikeCtx(context.Background(), tweet_id)
+}
 
-	response, err := c.delete_request(route)
+// Same as Unlike, but the request is bound to ctx.
+func (c *Client) UnlikeCtx(ctx context.Context, tweet_id string) (*LikeResponse, error) {
+
+	response, err := c.call_as_user(ctx, "Unlike", nil, tweet_id)
 
 	if err != nil {
 		return nil, err
@@ -265,30 +390,37 @@ func (c *Client) Unlike(tweet_id string) (*LikeResponse, error) {
 // References
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/get-tweets-id-liking_users
-func (c *Client) GetLikingUsers(tweet_id string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "media.fields", "place.fields",
-		"poll.fields", "tweet.fields", "user.fields",
-		"max_results", "pagination_token",
-	}
-
-	route := fmt.Sprintf("tweets/%s/liking_users", tweet_id)
+func (c *Client) GetLikingUsers(tweet_id string, params Parameters) (*UsersResponse, error) {
+	return c.GetLikingUsersCtx(context.Background(), tweet_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetLikingUsers, but the request is bound to ctx.
+func (c *Client) GetLikingUsersCtx(ctx context.Context, tweet_id string, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetLikingUsers", params, tweet_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: tweet_id, Params: params}
-	users := &UsersResponse{Caller: c.GetLikingUsers, CallerData: caller_data}
+	users := &UsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*UsersResponse, error) {
+		return c.GetLikingUsersCtx(ctx, tweet_id, params)
+	})
 
 	return users.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetLikingUsers.
+func (c *Client) GetLikingUsersIterator(tweet_id string, params Parameters) *Iterator[UsersResponse, entities.User] {
+	return new_iterator[UsersResponse, entities.User](func(ctx context.Context) (*UsersResponse, error) {
+		return c.GetLikingUsersCtx(ctx, tweet_id, params)
+	})
+}
+
 // Allows you to get information about a user’s liked Tweets.
 //
 // The Tweets returned by this endpoint count towards the Project-level `Tweet cap`.
@@ -305,28 +437,37 @@ func (c *Client) GetLikingUsers(tweet_id string, params Map) (*UsersResponse, er
 // References
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/get-users-id-liked_tweets
-func (c *Client) GetLikedTweets(user_id string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "media.fields",
-		"pagination_token", "place.fields", "poll.fields",
-		"tweet.fields", "user.fields",
-	}
-	route := fmt.Sprintf("users/%s/liked_tweets", user_id)
-	if params == nil {
-		params = make(Map)
+func (c *Client) GetLikedTweets(user_id string, params Parameters) (*TweetsResponse, error) {
+	return c.GetLikedTweetsCtx(context.Background(), user_id, params)
+}
+
+// Same as GetLikedTweets, but the request is bound to ctx.
+func (c *Client) GetLikedTweetsCtx(ctx context.Context, user_id string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetLikedTweets", params, user_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	tweets := &TweetsResponse{Caller: c.GetLikedTweets, CallerData: caller_data}
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.GetLikedTweetsCtx(ctx, user_id, params)
+	})
 
 	return tweets.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetLikedTweets.
+func (c *Client) GetLikedTweetsIterator(user_id string, params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.GetLikedTweetsCtx(ctx, user_id, params)
+	})
+}
+
 // ** Hide replies ** //
 
 // Hides a reply to a Tweet
@@ -341,17 +482,16 @@ func (c *Client) GetLikedTweets(user_id string, params Map) (*TweetsResponse, er
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/hide-replies/api-reference/put-tweets-id-hidden
 func (c *Client) HideReply(reply_id string) (*HideReplyResponse, error) {
+	return c.HideReplyCtx(context.Background(), reply_id)
+}
+
+// Same as HideReply, but the request is bound to ctx.
+func (c *Client) HideReplyCtx(ctx context.Context, reply_id string) (*HideReplyResponse, error) {
 	data := Map{
 		"hidden": true,
 	}
 
-	route := fmt.Sprintf("tweets/%s/hidden", reply_id)
-
-	response, err := c.request(
-		"PUT",
-		route,
-		data,
-	)
+	response, err := c.call(ctx, "HideReply", data, reply_id)
 
 	if err != nil {
 		return nil, err
@@ -372,17 +512,16 @@ func (c *Client) HideReply(reply_id string) (*HideReplyResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/hide-replies/api-reference/put-tweets-id-hidden
 func (c *Client) UnHideReply(reply_id string) (*HideReplyResponse, error) {
+	return c.UnHideReplyCtx(context.Background(), reply_id)
+}
+
+// Same as UnHideReply, but the request is bound to ctx.
+func (c *Client) UnHideReplyCtx(ctx context.Context, reply_id string) (*HideReplyResponse, error) {
 	data := Map{
 		"hidden": false,
 	}
 
-	route := fmt.Sprintf("tweets/%s/hidden", reply_id)
-
-	response, err := c.request(
-		"PUT",
-		route,
-		data,
-	)
+	response, err := c.call(ctx, "UnHideReply", data, reply_id)
 
 	if err != nil {
 		return nil, err
@@ -403,17 +542,16 @@ func (c *Client) UnHideReply(reply_id string) (*HideReplyResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/retweets/api-reference/post-users-id-retweets
 func (c *Client) Retweet(tweet_id string) (*RetweetResponse, error) {
+	return c.RetweetCtx(context.Background(), tweet_id)
+}
+
+// Same as Retweet, but the request is bound to ctx.
+func (c *Client) RetweetCtx(ctx context.Context, tweet_id string) (*RetweetResponse, error) {
 	data := Map{
 		"tweet_id": tweet_id,
 	}
 
-	route := fmt.Sprintf("users/%s/retweets", c.userID)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "Retweet", data)
 
 	if err != nil {
 		return nil, err
@@ -436,9 +574,13 @@ func (c *Client) Retweet(tweet_id string) (*RetweetResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/retweets/api-reference/delete-users-id-retweets-tweet_id
 func (c *Client) UnRetweet(tweet_id string) (*RetweetResponse, error) {
-	route := fmt.Sprintf("users/%s/retweets/%s", c.userID, tweet_id)
+	return c.UnRetweetCtx(context.Background(), tweet_id)
+}
+
+// Same as UnRetweet, but the request is bound to ctx.
+func (c *Client) UnRetweetCtx(ctx context.Context, tweet_id string) (*RetweetResponse, error) {
 
-	response, err := c.delete_request(route)
+	response, err := c.call_as_user(ctx, "UnRetweet", nil, tweet_id)
 
 	if err != nil {
 		return nil, err
@@ -461,60 +603,74 @@ func (c *Client) UnRetweet(tweet_id string) (*RetweetResponse, error) {
 // References
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/retweets/api-reference/get-tweets-id-retweeted_by
-func (c *Client) GetRetweeters(tweet_id string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "media.fields", "place.fields",
-		"poll.fields", "tweet.fields", "user.fields",
-		"max_results", "pagination_token",
-	}
-
-	route := fmt.Sprintf("tweets/%s/retweeted_by", tweet_id)
+func (c *Client) GetRetweeters(tweet_id string, params Parameters) (*UsersResponse, error) {
+	return c.GetRetweetersCtx(context.Background(), tweet_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetRetweeters, but the request is bound to ctx.
+func (c *Client) GetRetweetersCtx(ctx context.Context, tweet_id string, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetRetweeters", params, tweet_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: tweet_id, Params: params}
-	users := &UsersResponse{Caller: c.GetRetweeters, CallerData: caller_data}
+	users := &UsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*UsersResponse, error) {
+		return c.GetRetweetersCtx(ctx, tweet_id, params)
+	})
 
 	return users.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetRetweeters.
+func (c *Client) GetRetweetersIterator(tweet_id string, params Parameters) *Iterator[UsersResponse, entities.User] {
+	return new_iterator[UsersResponse, entities.User](func(ctx context.Context) (*UsersResponse, error) {
+		return c.GetRetweetersCtx(ctx, tweet_id, params)
+	})
+}
+
 // Returns Quote Tweets for a Tweet specified by the requested Tweet ID.
 //
 // The Tweets returned by this endpoint count towards the Project-level
 // `Tweet cap`.
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/quote-tweets/api-reference/get-tweets-id-quote_tweets
-func (c *Client) GetQuoteTweets(tweet_id string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "media.fields", "place.fields",
-		"poll.fields", "tweet.fields", "user.fields",
-		"max_results", "pagination_token",
-	}
-
-	route := fmt.Sprintf("tweets/%s/quoted_tweets", tweet_id)
+func (c *Client) GetQuoteTweets(tweet_id string, params Parameters) (*TweetsResponse, error) {
+	return c.GetQuoteTweetsCtx(context.Background(), tweet_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetQuoteTweets, but the request is bound to ctx.
+func (c *Client) GetQuoteTweetsCtx(ctx context.Context, tweet_id string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetQuoteTweets", params, tweet_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: tweet_id, Params: params}
-	tweets := &TweetsResponse{Caller: c.GetQuoteTweets, CallerData: caller_data}
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.GetQuoteTweetsCtx(ctx, tweet_id, params)
+	})
 
 	return tweets.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetQuoteTweets.
+func (c *Client) GetQuoteTweetsIterator(tweet_id string, params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.GetQuoteTweetsCtx(ctx, tweet_id, params)
+	})
+}
+
 // ** Search tweets ** //
 
 // The full-archive search endpoint returns the complete history of public
@@ -571,34 +727,46 @@ func (c *Client) GetQuoteTweets(tweet_id string, params Map) (*TweetsResponse, e
 // Tweet cap: https://developer.twitter.com/en/docs/projects/overview#tweet-cap
 //
 // pagination: https://developer.twitter.com/en/docs/twitter-api/tweets/search/integrate/paginate
-func (c *Client) SearchAllTweets(query string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"end_time", "expansions", "max_results", "media.fields",
-		"next_token", "place.fields", "poll.fields", "query",
-		"since_id", "start_time", "tweet.fields", "until_id",
-		"user.fields",
-	}
-	route := "tweets/search/all"
-	if params == nil {
-		params = make(Map)
-	} else if val, ok := params["pagination_token"]; ok {
+func (c *Client) SearchAllTweets(query string, params Parameters) (*TweetsResponse, error) {
+	return c.SearchAllTweetsCtx(context.Background(), query, params)
+}
+
+// Same as SearchAllTweets, but the request is bound to ctx.
+func (c *Client) SearchAllTweetsCtx(ctx context.Context, query string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
+	}
+	if val, ok := params["pagination_token"]; ok {
 		params["next_token"] = val
 		delete(params, "pagination_token")
 	}
 
+	if err := c.check_query(query, true); err != nil {
+		return nil, err
+	}
 	params["query"] = query
 
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "SearchAllTweets", params)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: query, Params: params}
-	tweets := &TweetsResponse{Caller: c.GetUserTweets, CallerData: caller_data}
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "next_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.SearchAllTweetsCtx(ctx, query, params)
+	})
 
 	return tweets.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of SearchAllTweets.
+func (c *Client) SearchAllTweetsIterator(query string, params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.SearchAllTweetsCtx(ctx, query, params)
+	})
+}
+
 // The recent search endpoint returns Tweets from the last seven days that match a search query.
 //
 // The Tweets returned by this endpoint count towards the Project-level
@@ -659,34 +827,49 @@ func (c *Client) SearchAllTweets(query string, params Map) (*TweetsResponse, err
 // operators: https://developer.twitter.com/en/docs/twitter-api/tweets/search/integrate/build-a-query
 //
 // Academic Research Project: https://developer.twitter.com/en/docs/projects
-func (c *Client) SearchRecentTweets(query string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"end_time", "expansions", "max_results", "media.fields",
-		"next_token", "place.fields", "poll.fields", "query",
-		"since_id", "start_time", "tweet.fields", "until_id",
-		"user.fields",
-	}
-	route := "tweets/search/recent"
-	if params == nil {
-		params = make(Map)
-	} else if val, ok := params["pagination_token"]; ok {
+func (c *Client) SearchRecentTweets(query string, params Parameters) (*TweetsResponse, error) {
+	return c.SearchRecentTweetsCtx(context.Background(), query, params)
+}
+
+// Same as SearchRecentTweets, but the request is bound to ctx.
+func (c *Client) SearchRecentTweetsCtx(ctx context.Context, query string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
+	}
+	if val, ok := params["pagination_token"]; ok {
 		params["next_token"] = val
 		delete(params, "pagination_token")
 	}
+	if err := check_max_results(params, 100); err != nil {
+		return nil, err
+	}
 
+	if err := c.check_query(query, false); err != nil {
+		return nil, err
+	}
 	params["query"] = query
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "SearchRecentTweets", params)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: query, Params: params}
-	tweets := &TweetsResponse{Caller: c.GetUserTweets, CallerData: caller_data}
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "next_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.SearchRecentTweetsCtx(ctx, query, params)
+	})
 
 	return tweets.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of SearchRecentTweets.
+func (c *Client) SearchRecentTweetsIterator(query string, params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.SearchRecentTweetsCtx(ctx, query, params)
+	})
+}
+
 // ** Timelines ** //
 
 // Returns Tweets composed by a single user, specified by the requested
@@ -697,30 +880,37 @@ func (c *Client) SearchRecentTweets(query string, params Map) (*TweetsResponse,
 // The Tweets returned by this endpoint count towards the Project-level `Tweet cap`.
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/timelines/api-reference/get-users-id-tweets
-func (c *Client) GetUserTweets(user_id string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"end_time", "exclude", "expansions", "max_results",
-		"media.fields", "pagination_token", "place.fields",
-		"poll.fields", "since_id", "start_time", "tweet.fields",
-		"until_id", "user.fields",
-	}
-	route := fmt.Sprintf("users/%s/tweets", user_id)
+func (c *Client) GetUserTweets(user_id string, params Parameters) (*TweetsResponse, error) {
+	return c.GetUserTweetsCtx(context.Background(), user_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetUserTweets, but the request is bound to ctx.
+func (c *Client) GetUserTweetsCtx(ctx context.Context, user_id string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetUserTweets", params, user_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	tweets := &TweetsResponse{Caller: c.GetUserTweets, CallerData: caller_data}
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.GetUserTweetsCtx(ctx, user_id, params)
+	})
 
 	return tweets.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetUserTweets.
+func (c *Client) GetUserTweetsIterator(user_id string, params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.GetUserTweetsCtx(ctx, user_id, params)
+	})
+}
+
 // Returns Tweets mentioning a single user specified by the requested user
 // ID. By default, the most recent ten Tweets are returned per request.
 // Using pagination, up to the most recent 800 Tweets can be retrieved.
@@ -728,30 +918,80 @@ func (c *Client) GetUserTweets(user_id string, params Map) (*TweetsResponse, err
 // The Tweets returned by this endpoint count towards the Project-level `Tweet cap`.
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/timelines/api-reference/get-users-id-mentions
-func (c *Client) GetUserMentions(user_id string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"end_time", "expansions", "max_results", "media.fields",
-		"pagination_token", "place.fields", "poll.fields", "since_id",
-		"start_time", "tweet.fields", "until_id", "user.fields",
+func (c *Client) GetUserMentions(user_id string, params Parameters) (*TweetsResponse, error) {
+	return c.GetUserMentionsCtx(context.Background(), user_id, params)
+}
+
+// Same as GetUserMentions, but the request is bound to ctx.
+func (c *Client) GetUserMentionsCtx(ctx context.Context, user_id string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	route := fmt.Sprintf("users/%s/mentions", user_id)
+	response, err := c.call(ctx, "GetUserMentions", params, user_id)
+	if err != nil {
+		return nil, err
+	}
 
-	if params == nil {
-		params = make(Map)
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.GetUserMentionsCtx(ctx, user_id, params)
+	})
+
+	return tweets.Parse(response)
+}
+
+// Returns an Iterator over the pages or the items of GetUserMentions.
+func (c *Client) GetUserMentionsIterator(user_id string, params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.GetUserMentionsCtx(ctx, user_id, params)
+	})
+}
+
+// Returns the home timeline of the authenticated user, the Tweets of the users they follow,
+// their own Tweets and the Retweets of them, in reverse chronological order.
+// Using pagination, up to the most recent 3,200 Tweets can be retrieved,
+// use WatchHomeTimeline to get the new ones as they come.
+//
+// Parameters
+//
+// params: Either a *TimelineOptions, or a Map of the raw query parameters.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/tweets/timelines/api-reference/get-users-id-reverse-chronological
+func (c *Client) GetHomeTimeline(params Parameters) (*TweetsResponse, error) {
+	return c.GetHomeTimelineCtx(context.Background(), params)
+}
+
+// Same as GetHomeTimeline, but the request is bound to ctx.
+func (c *Client) GetHomeTimelineCtx(ctx context.Context, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call_as_user(ctx, "GetHomeTimeline", params)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	tweets := &TweetsResponse{Caller: c.GetUserMentions, CallerData: caller_data}
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.GetHomeTimelineCtx(ctx, params)
+	})
 
 	return tweets.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetHomeTimeline.
+func (c *Client) GetHomeTimelineIterator(params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.GetHomeTimelineCtx(ctx, params)
+	})
+}
+
 // ** Tweet counts ** //
 
 // This endpoint is only available to those users who have been approved
@@ -761,45 +1001,71 @@ func (c *Client) GetUserMentions(user_id string, params Map) (*TweetsResponse, e
 // Tweets matching a search query; since the first Tweet was created March
 // 26, 2006.
 //
+// Results are paginated, use NextPage, GetAllTweetsCountIterator,
+// or GetAllTweetsCountSeries to get the buckets of all pages.
+//
+// params: Either a *CountsOptions or a Map, "granularity" is "hour" by default.
+//
 // https://developer.twitter.com/en/docs/twitter-api/tweets/counts/api-reference/get-tweets-counts-all
-func (c *Client) GetAllTweetsCount(query string, params Map) (*TweetsCountResponse, error) {
-	endpoint_parameters := []string{
-		"end_time", "granularity", "next_token", "query",
-		"since_id", "start_time", "until_id",
-	}
+func (c *Client) GetAllTweetsCount(query string, params Parameters) (*TweetsCountResponse, error) {
+	return c.GetAllTweetsCountCtx(context.Background(), query, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetAllTweetsCount, but the request is bound to ctx.
+func (c *Client) GetAllTweetsCountCtx(ctx context.Context, query string, options Parameters) (*TweetsCountResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
+	if err := c.check_query(query, true); err != nil {
+		return nil, err
+	}
 	params["query"] = query
 
-	route := "tweets/counts/all"
-
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetAllTweetsCount", params)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return (&TweetsCountResponse{}).Parse(response)
+	counts := &TweetsCountResponse{ctx: ctx}
+	counts.fetch = fetcher(params, "next_token", func(ctx context.Context, params Map) (*TweetsCountResponse, error) {
+		return c.GetAllTweetsCountCtx(ctx, query, params)
+	})
+
+	return counts.Parse(response)
+}
+
+// Returns an Iterator over the pages or the buckets of GetAllTweetsCount.
+func (c *Client) GetAllTweetsCountIterator(query string, params Parameters) *Iterator[TweetsCountResponse, CountBucket] {
+	return new_iterator[TweetsCountResponse, CountBucket](func(ctx context.Context) (*TweetsCountResponse, error) {
+		return c.GetAllTweetsCountCtx(ctx, query, params)
+	})
 }
 
 // The recent Tweet counts endpoint returns count of Tweets from the last
 // seven days that match a search query.
 //
+// params: Either a *CountsOptions or a Map, "granularity" is "hour" by default.
+//
 // https://developer.twitter.com/en/docs/twitter-api/tweets/counts/api-reference/get-tweets-counts-recent
-func (c *Client) GetRecentTweetsCount(query string, params Map) (*TweetsCountResponse, error) {
-	endpoint_parameters := []string{
-		"end_time", "granularity", "query",
-		"since_id", "start_time", "until_id",
+func (c *Client) GetRecentTweetsCount(query string, params Parameters) (*TweetsCountResponse, error) {
+	return c.GetRecentTweetsCountCtx(context.Background(), query, params)
+}
+
+// Same as GetRecentTweetsCount, but the request is bound to ctx.
+func (c *Client) GetRecentTweetsCountCtx(ctx context.Context, query string, options Parameters) (*TweetsCountResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
-	if params == nil {
-		params = make(Map)
+	if err := c.check_query(query, false); err != nil {
+		return nil, err
 	}
 	params["query"] = query
 
-	response, err := c.get_request("tweets/counts/recent", OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetRecentTweetsCount", params)
 
 	if err != nil {
 		return nil, err
@@ -814,13 +1080,18 @@ func (c *Client) GetRecentTweetsCount(query string, params Map) (*TweetsCountRes
 // the requested ID.
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/lookup/api-reference/get-tweets-id
-func (c *Client) GetTweet(tweet_id string, params Map) (*TweetResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "media.fields", "place.fields",
-		"poll.fields", "tweet.fields", "user.fields",
+func (c *Client) GetTweet(tweet_id string, params Parameters) (*TweetResponse, error) {
+	return c.GetTweetCtx(context.Background(), tweet_id, params)
+}
+
+// Same as GetTweet, but the request is bound to ctx.
+func (c *Client) GetTweetCtx(ctx context.Context, tweet_id string, options Parameters) (*TweetResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
-	route := fmt.Sprintf("tweets/%s", tweet_id)
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+
+	response, err := c.call(ctx, "GetTweet", params, tweet_id)
 	if err != nil {
 		return nil, err
 	}
@@ -831,16 +1102,18 @@ func (c *Client) GetTweet(tweet_id string, params Map) (*TweetResponse, error) {
 // requested ID or list of IDs.
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/lookup/api-reference/get-tweets
-func (c *Client) GetTweets(tweet_ids []string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"ids", "expansions", "media.fields", "place.fields",
-		"poll.fields", "tweet.fields", "user.fields",
-	}
-	if params == nil {
-		params = make(Map)
+func (c *Client) GetTweets(tweet_ids []string, params Parameters) (*TweetsResponse, error) {
+	return c.GetTweetsCtx(context.Background(), tweet_ids, params)
+}
+
+// Same as GetTweets, but the request is bound to ctx.
+func (c *Client) GetTweetsCtx(ctx context.Context, tweet_ids []string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 	params["ids"] = tweet_ids
-	response, err := c.get_request("tweets", c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetTweets", params)
 	if err != nil {
 		return nil, err
 	}
@@ -851,17 +1124,16 @@ func (c *Client) GetTweets(tweet_ids []string, params Map) (*TweetsResponse, err
 
 // ** Blocks ** //
 func (c *Client) Block(target_user_id string) (*BlockResponse, error) {
+	return c.BlockCtx(context.Background(), target_user_id)
+}
+
+// Same as Block, but the request is bound to ctx.
+func (c *Client) BlockCtx(ctx context.Context, target_user_id string) (*BlockResponse, error) {
 	data := Map{
 		"target_user_id": target_user_id,
 	}
 
-	route := fmt.Sprintf("users/%s/blocking", c.userID)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "Block", data)
 
 	if err != nil {
 		return nil, err
@@ -875,9 +1147,13 @@ func (c *Client) Block(target_user_id string) (*BlockResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/blocks/api-reference/delete-users-user_id-blocking
 func (c *Client) UnBlock(target_user_id string) (*BlockResponse, error) {
-	route := fmt.Sprintf("users/%s/blocking/%s", c.userID, target_user_id)
+	return c.UnBlockCtx(context.Background(), target_user_id)
+}
+
+// Same as UnBlock, but the request is bound to ctx.
+func (c *Client) UnBlockCtx(ctx context.Context, target_user_id string) (*BlockResponse, error) {
 
-	response, err := c.delete_request(route)
+	response, err := c.call_as_user(ctx, "UnBlock", nil, target_user_id)
 
 	if err != nil {
 		return nil, err
@@ -889,25 +1165,35 @@ func (c *Client) UnBlock(target_user_id string) (*BlockResponse, error) {
 // Returns a list of users who are blocked by the authenticating user.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/blocks/api-reference/get-users-blocking
-func (c *Client) GetBlocked(params Map) (*UsersResponse, error) {
-	// TODO: PAGINATION PROBLEM
-	endpoint_parameters := []string{
-		"expansions", "max_results", "tweet.fields",
-		"user.fields", "pagination_token",
-	}
-
-	route := fmt.Sprintf("users/%s/blocking", c.userID)
+func (c *Client) GetBlocked(params Parameters) (*UsersResponse, error) {
+	return c.GetBlockedCtx(context.Background(), params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetBlocked, but the request is bound to ctx.
+func (c *Client) GetBlockedCtx(ctx context.Context, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, OAuth_1a, params, endpoint_parameters)
+	response, err := c.call_as_user(ctx, "GetBlocked", params)
 	if err != nil {
 		return nil, err
 	}
 
-	return (&UsersResponse{}).Parse(response)
+	users := &UsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*UsersResponse, error) {
+		return c.GetBlockedCtx(ctx, params)
+	})
+
+	return users.Parse(response)
+}
+
+// Returns an Iterator over the pages or the items of GetBlocked.
+func (c *Client) GetBlockedIterator(params Parameters) *Iterator[UsersResponse, entities.User] {
+	return new_iterator[UsersResponse, entities.User](func(ctx context.Context) (*UsersResponse, error) {
+		return c.GetBlockedCtx(ctx, params)
+	})
 }
 
 // ** Follows ** //
@@ -922,18 +1208,21 @@ func (c *Client) GetBlocked(params Map) (*UsersResponse, error) {
 // follower request to a user that does not have public Tweets.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/post-users-source_user_id-following
-func (c *Client) FollowUser(target_user_id string, params Map) (*FollowResponse, error) {
-	data := Map{
-		"target_user_id": target_user_id,
+func (c *Client) FollowUser(target_user_id string, params Parameters) (*FollowResponse, error) {
+	return c.FollowUserCtx(context.Background(), target_user_id, params)
+}
+
+// Same as FollowUser, but the request is bound to ctx.
+func (c *Client) FollowUserCtx(ctx context.Context, target_user_id string, options Parameters) (*FollowResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	route := fmt.Sprintf("users/%s/following", c.userID)
+	data := params
+	data["target_user_id"] = target_user_id
 
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "FollowUser", data)
 
 	if err != nil {
 		return nil, err
@@ -949,9 +1238,13 @@ func (c *Client) FollowUser(target_user_id string, params Map) (*FollowResponse,
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/delete-users-source_id-following
 func (c *Client) UnfollowUser(target_user_id string) (*FollowResponse, error) {
-	route := fmt.Sprintf("users/%s/following/%s", c.userID, target_user_id)
+	return c.UnfollowUserCtx(context.Background(), target_user_id)
+}
 
-	response, err := c.delete_request(route)
+// Same as UnfollowUser, but the request is bound to ctx.
+func (c *Client) UnfollowUserCtx(ctx context.Context, target_user_id string) (*FollowResponse, error) {
+
+	response, err := c.call_as_user(ctx, "UnfollowUser", nil, target_user_id)
 	if err != nil {
 		return nil, err
 	}
@@ -962,71 +1255,86 @@ func (c *Client) UnfollowUser(target_user_id string) (*FollowResponse, error) {
 // Returns a list of users who are followers of the specified user ID.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/get-users-id-followers
-func (c *Client) GetUserFollowers(user_id string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "tweet.fields",
-		"user.fields", "pagination_token",
-	}
-
-	route := fmt.Sprintf("users/%s/followers", user_id)
+func (c *Client) GetUserFollowers(user_id string, params Parameters) (*UsersResponse, error) {
+	return c.GetUserFollowersCtx(context.Background(), user_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetUserFollowers, but the request is bound to ctx.
+func (c *Client) GetUserFollowersCtx(ctx context.Context, user_id string, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetUserFollowers", params, user_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	users := &UsersResponse{Caller: c.GetUserFollowers, CallerData: caller_data}
+	users := &UsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*UsersResponse, error) {
+		return c.GetUserFollowersCtx(ctx, user_id, params)
+	})
 
 	return users.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetUserFollowers.
+func (c *Client) GetUserFollowersIterator(user_id string, params Parameters) *Iterator[UsersResponse, entities.User] {
+	return new_iterator[UsersResponse, entities.User](func(ctx context.Context) (*UsersResponse, error) {
+		return c.GetUserFollowersCtx(ctx, user_id, params)
+	})
+}
+
 // Returns a list of users the specified user ID is following
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/get-users-id-following
-func (c *Client) GetUserFollowing(user_id string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "tweet.fields",
-		"user.fields", "pagination_token",
-	}
-
-	route := fmt.Sprintf("users/%s/following", user_id)
+func (c *Client) GetUserFollowing(user_id string, params Parameters) (*UsersResponse, error) {
+	return c.GetUserFollowingCtx(context.Background(), user_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetUserFollowing, but the request is bound to ctx.
+func (c *Client) GetUserFollowingCtx(ctx context.Context, user_id string, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetUserFollowing", params, user_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	users := &UsersResponse{Caller: c.GetUserFollowing, CallerData: caller_data}
+	users := &UsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*UsersResponse, error) {
+		return c.GetUserFollowingCtx(ctx, user_id, params)
+	})
 
 	return users.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetUserFollowing.
+func (c *Client) GetUserFollowingIterator(user_id string, params Parameters) *Iterator[UsersResponse, entities.User] {
+	return new_iterator[UsersResponse, entities.User](func(ctx context.Context) (*UsersResponse, error) {
+		return c.GetUserFollowingCtx(ctx, user_id, params)
+	})
+}
+
 // ** Mutes ** //
 // Allows an authenticated user ID to mute the target user.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/mutes/api-reference/post-users-user_id-muting
 func (c *Client) Mute(target_user_id string) (*MuteResponse, error) {
+	return c.MuteCtx(context.Background(), target_user_id)
+}
+
+// Same as Mute, but the request is bound to ctx.
+func (c *Client) MuteCtx(ctx context.Context, target_user_id string) (*MuteResponse, error) {
 	data := Map{
 		"target_user_id": target_user_id,
 	}
 
-	route := fmt.Sprintf("users/%s/muting", c.userID)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "Mute", data)
 
 	if err != nil {
 		return nil, err
@@ -1042,9 +1350,13 @@ func (c *Client) Mute(target_user_id string) (*MuteResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/mutes/api-reference/delete-users-user_id-muting
 func (c *Client) UnMute(target_user_id string) (*MuteResponse, error) {
-	route := fmt.Sprintf("users/%s/muting/%s", c.userID, target_user_id)
+	return c.UnMuteCtx(context.Background(), target_user_id)
+}
+
+// Same as UnMute, but the request is bound to ctx.
+func (c *Client) UnMuteCtx(ctx context.Context, target_user_id string) (*MuteResponse, error) {
 
-	response, err := c.delete_request(route)
+	response, err := c.call_as_user(ctx, "UnMute", nil, target_user_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1055,46 +1367,55 @@ func (c *Client) UnMute(target_user_id string) (*MuteResponse, error) {
 // Returns a list of users who are muted by the authenticating user.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/mutes/api-reference/get-users-muting
-func (c *Client) GetMuted(params Map) (*MutedUsersResponse, error) {
-	// TODO: PAGINATION PROBLEM
-	endpoint_parameters := []string{
-		"expansions", "max_results", "tweet.fields",
-		"user.fields", "pagination_token",
-	}
-	route := fmt.Sprintf("users/%s/muting", c.userID)
+func (c *Client) GetMuted(params Parameters) (*MutedUsersResponse, error) {
+	return c.GetMutedCtx(context.Background(), params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetMuted, but the request is bound to ctx.
+func (c *Client) GetMutedCtx(ctx context.Context, options Parameters) (*MutedUsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call_as_user(ctx, "GetMuted", params)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: "", Params: params}
-	users := &MutedUsersResponse{Caller: c.GetMuted, CallerData: caller_data}
+	users := &MutedUsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*MutedUsersResponse, error) {
+		return c.GetMutedCtx(ctx, params)
+	})
 
 	return users.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetMuted.
+func (c *Client) GetMutedIterator(params Parameters) *Iterator[MutedUsersResponse, entities.User] {
+	return new_iterator[MutedUsersResponse, entities.User](func(ctx context.Context) (*MutedUsersResponse, error) {
+		return c.GetMutedCtx(ctx, params)
+	})
+}
+
 // ** User lookup ** //
 
-// Returns information about an authorized user.
+// Returns information about an authorized user,
+// it always uses the user context, so the client needs an access token.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-me
-func (c *Client) GetMe(oauth_1a bool, params Map) (*UserResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "tweet.fields", "user.fields",
-	}
-
-	route := "users/me"
+func (c *Client) GetMe(params Parameters) (*UserResponse, error) {
+	return c.GetMeCtx(context.Background(), params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetMe, but the request is bound to ctx.
+func (c *Client) GetMeCtx(ctx context.Context, options Parameters) (*UserResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetMe", params)
 	if err != nil {
 		return nil, err
 	}
@@ -1106,12 +1427,18 @@ func (c *Client) GetMe(oauth_1a bool, params Map) (*UserResponse, error) {
 // requested ID.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-id
-func (c *Client) GetUserByID(user_id string, params Map) (*UserResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "tweet.fields", "user.fields",
+func (c *Client) GetUserByID(user_id string, params Parameters) (*UserResponse, error) {
+	return c.GetUserByIDCtx(context.Background(), user_id, params)
+}
+
+// Same as GetUserByID, but the request is bound to ctx.
+func (c *Client) GetUserByIDCtx(ctx context.Context, user_id string, options Parameters) (*UserResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
-	route := fmt.Sprintf("users/%s", user_id)
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+
+	response, err := c.call(ctx, "GetUserByID", params, user_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1122,13 +1449,19 @@ func (c *Client) GetUserByID(user_id string, params Map) (*UserResponse, error)
 // requested username.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-by-username-username
-func (c *Client) GetUserByUsername(username string, params Map) (*UserResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "tweet.fields", "user.fields",
+func (c *Client) GetUserByUsername(username string, params Parameters) (*UserResponse, error) {
+	return c.GetUserByUsernameCtx(context.Background(), username, params)
+}
+
+// Same as GetUserByUsername, but the request is bound to ctx.
+func (c *Client) GetUserByUsernameCtx(ctx context.Context, username string, options Parameters) (*UserResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
+
 	username = strings.Replace(username, "@", "", 1)
-	route := fmt.Sprintf("users/by/username/%s", username)
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetUserByUsername", params, username)
 	if err != nil {
 		return nil, err
 	}
@@ -1139,21 +1472,22 @@ func (c *Client) GetUserByUsername(username string, params Map) (*UserResponse,
 // the requested IDs.
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users
-func (c *Client) GetUsersByIDs(user_ids []string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"usernames", "ids", "expansions",
-		"tweet.fields", "user.fields",
-	}
+func (c *Client) GetUsersByIDs(user_ids []string, params Parameters) (*UsersResponse, error) {
+	return c.GetUsersByIDsCtx(context.Background(), user_ids, params)
+}
 
+// Same as GetUsersByIDs, but the request is bound to ctx.
+func (c *Client) GetUsersByIDsCtx(ctx context.Context, user_ids []string, options Parameters) (*UsersResponse, error) {
 	if user_ids == nil {
 		return nil, fmt.Errorf("user_ids are required")
 	}
-	if params == nil {
-		params = make(Map)
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 	params["ids"] = user_ids
 
-	response, err := c.get_request("users", c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetUsersByIDs", params)
 	if err != nil {
 		return nil, err
 	}
@@ -1166,21 +1500,22 @@ func (c *Client) GetUsersByIDs(user_ids []string, params Map) (*UsersResponse, e
 // usernames should not have @ at the beginning
 //
 // https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-by
-func (c *Client) GetUsersByUsernames(usernames []string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"usernames", "ids", "expansions",
-		"tweet.fields", "user.fields",
-	}
+func (c *Client) GetUsersByUsernames(usernames []string, params Parameters) (*UsersResponse, error) {
+	return c.GetUsersByUsernamesCtx(context.Background(), usernames, params)
+}
 
+// Same as GetUsersByUsernames, but the request is bound to ctx.
+func (c *Client) GetUsersByUsernamesCtx(ctx context.Context, usernames []string, options Parameters) (*UsersResponse, error) {
 	if usernames == nil {
 		return nil, fmt.Errorf("usernames are required")
 	}
-	if params == nil {
-		params = make(Map)
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 	params["usernames"] = usernames
 
-	response, err := c.get_request("users/by", c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetUsersByUsernames", params)
 	if err != nil {
 		return nil, err
 	}
@@ -1192,17 +1527,18 @@ func (c *Client) GetUsersByUsernames(usernames []string, params Map) (*UsersResp
 // Return live or scheduled Spaces matching your specified search terms
 //
 // https://developer.twitter.com/en/docs/twitter-api/spaces/search/api-reference/get-spaces-search
-func (c *Client) SearchSpaces(query string, params Map) (*SpacesResponse, error) {
-	endpoint_parameters := []string{
-		"query", "expansions", "max_results",
-		"space.fields", "state", "user.fields",
-	}
-	route := "spaces/search"
-	if params == nil {
-		params = make(Map)
+func (c *Client) SearchSpaces(query string, params Parameters) (*SpacesResponse, error) {
+	return c.SearchSpacesCtx(context.Background(), query, params)
+}
+
+// Same as SearchSpaces, but the request is bound to ctx.
+func (c *Client) SearchSpacesCtx(ctx context.Context, query string, options Parameters) (*SpacesResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 	params["query"] = query
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "SearchSpaces", params)
 	if err != nil {
 		return nil, err
 	}
@@ -1214,16 +1550,18 @@ func (c *Client) SearchSpaces(query string, params Map) (*SpacesResponse, error)
 // Up to 100 comma-separated Space IDs can be looked up using this method.
 //
 // https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces
-func (c *Client) GetSpacesBySpaceIDs(space_ids []string, params Map) (*SpacesResponse, error) {
-	endpoint_parameters := []string{
-		"ids", "user_ids", "expansions", "space.fields", "user.fields",
-	}
-	route := "spaces"
-	if params == nil {
-		params = make(Map)
+func (c *Client) GetSpacesBySpaceIDs(space_ids []string, params Parameters) (*SpacesResponse, error) {
+	return c.GetSpacesBySpaceIDsCtx(context.Background(), space_ids, params)
+}
+
+// Same as GetSpacesBySpaceIDs, but the request is bound to ctx.
+func (c *Client) GetSpacesBySpaceIDsCtx(ctx context.Context, space_ids []string, options Parameters) (*SpacesResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 	params["ids"] = space_ids
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetSpacesBySpaceIDs", params)
 	if err != nil {
 		return nil, err
 	}
@@ -1235,16 +1573,18 @@ func (c *Client) GetSpacesBySpaceIDs(space_ids []string, params Map) (*SpacesRes
 // Up to 100 comma-separated user IDs can be looked up using this method.
 //
 // https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces-by-creator-ids
-func (c *Client) GetSpacesByCreatorIDs(creator_ids []string, params Map) (*SpacesResponse, error) {
-	endpoint_parameters := []string{
-		"ids", "user_ids", "expansions", "space.fields", "user.fields",
-	}
-	route := "spaces/by/creator_ids"
-	if params == nil {
-		params = make(Map)
+func (c *Client) GetSpacesByCreatorIDs(creator_ids []string, params Parameters) (*SpacesResponse, error) {
+	return c.GetSpacesByCreatorIDsCtx(context.Background(), creator_ids, params)
+}
+
+// Same as GetSpacesByCreatorIDs, but the request is bound to ctx.
+func (c *Client) GetSpacesByCreatorIDsCtx(ctx context.Context, creator_ids []string, options Parameters) (*SpacesResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 	params["user_ids"] = creator_ids
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetSpacesByCreatorIDs", params)
 	if err != nil {
 		return nil, err
 	}
@@ -1255,12 +1595,18 @@ func (c *Client) GetSpacesByCreatorIDs(creator_ids []string, params Map) (*Space
 // requested ID.
 //
 // https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces-id
-func (c *Client) GetSpace(space_id string, params Map) (*SpaceResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "space.fields", "user.fields",
+func (c *Client) GetSpace(space_id string, params Parameters) (*SpaceResponse, error) {
+	return c.GetSpaceCtx(context.Background(), space_id, params)
+}
+
+// Same as GetSpace, but the request is bound to ctx.
+func (c *Client) GetSpaceCtx(ctx context.Context, space_id string, options Parameters) (*SpaceResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
-	route := fmt.Sprintf("spaces/%s", space_id)
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+
+	response, err := c.call(ctx, "GetSpace", params, space_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1272,13 +1618,18 @@ func (c *Client) GetSpace(space_id string, params Map) (*SpaceResponse, error) {
 // of the requested Space.
 //
 // https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces-id-buyers
-func (c *Client) GetSpaceBuyers(space_id string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "media.fields", "place.fields",
-		"poll.fields", "tweet.fields", "user.fields",
+func (c *Client) GetSpaceBuyers(space_id string, params Parameters) (*UsersResponse, error) {
+	return c.GetSpaceBuyersCtx(context.Background(), space_id, params)
+}
+
+// Same as GetSpaceBuyers, but the request is bound to ctx.
+func (c *Client) GetSpaceBuyersCtx(ctx context.Context, space_id string, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
-	route := fmt.Sprintf("spaces/%s/buyers", space_id)
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+
+	response, err := c.call(ctx, "GetSpaceBuyers", params, space_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1288,19 +1639,18 @@ func (c *Client) GetSpaceBuyers(space_id string, params Map) (*UsersResponse, er
 // Returns Tweets shared in the requested Spaces.
 //
 // https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces-id-tweets
-func (c *Client) GetSpaceTweets(space_id string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "media.fields", "place.fields",
-		"poll.fields", "tweet.fields", "user.fields",
-	}
-
-	route := fmt.Sprintf("spaces/%s/tweets", space_id)
+func (c *Client) GetSpaceTweets(space_id string, params Parameters) (*TweetsResponse, error) {
+	return c.GetSpaceTweetsCtx(context.Background(), space_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetSpaceTweets, but the request is bound to ctx.
+func (c *Client) GetSpaceTweetsCtx(ctx context.Context, space_id string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetSpaceTweets", params, space_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1313,45 +1663,53 @@ func (c *Client) GetSpaceTweets(space_id string, params Map) (*TweetsResponse, e
 // Returns a list of Tweets from the specified List.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-tweets/api-reference/get-lists-id-tweets
-func (c *Client) GetListTweets(list_id string, params Map) (*TweetsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "pagination_token",
-		"tweet.fields", "user.fields",
-	}
-	route := fmt.Sprintf("lists/%s/tweets", list_id)
+func (c *Client) GetListTweets(list_id string, params Parameters) (*TweetsResponse, error) {
+	return c.GetListTweetsCtx(context.Background(), list_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetListTweets, but the request is bound to ctx.
+func (c *Client) GetListTweetsCtx(ctx context.Context, list_id string, options Parameters) (*TweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetListTweets", params, list_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: list_id, Params: params}
-	tweets := &TweetsResponse{Caller: c.GetListTweets, CallerData: caller_data}
+	tweets := &TweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*TweetsResponse, error) {
+		return c.GetListTweetsCtx(ctx, list_id, params)
+	})
 
 	return tweets.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetListTweets.
+func (c *Client) GetListTweetsIterator(list_id string, params Parameters) *Iterator[TweetsResponse, entities.Tweet] {
+	return new_iterator[TweetsResponse, entities.Tweet](func(ctx context.Context) (*TweetsResponse, error) {
+		return c.GetListTweetsCtx(ctx, list_id, params)
+	})
+}
+
 // ** List follows ** //
 
 // Enables the authenticated user to follow a List.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-follows/api-reference/post-users-id-followed-lists
 func (c *Client) FollowList(list_id string) (*FollowResponse, error) {
+	return c.FollowListCtx(context.Background(), list_id)
+}
+
+// Same as FollowList, but the request is bound to ctx.
+func (c *Client) FollowListCtx(ctx context.Context, list_id string) (*FollowResponse, error) {
 	data := Map{
 		"list_id": list_id,
 	}
 
-	route := fmt.Sprintf("users/%s/followed_lists", c.userID)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "FollowList", data)
 
 	if err != nil {
 		return nil, err
@@ -1365,9 +1723,13 @@ func (c *Client) FollowList(list_id string) (*FollowResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-follows/api-reference/delete-users-id-followed-lists-list_id
 func (c *Client) UnfollowList(list_id string) (*FollowResponse, error) {
-	route := fmt.Sprintf("users/%s/followed_lists/%s", c.userID, list_id)
+	return c.UnfollowListCtx(context.Background(), list_id)
+}
 
-	response, err := c.delete_request(route)
+// Same as UnfollowList, but the request is bound to ctx.
+func (c *Client) UnfollowListCtx(ctx context.Context, list_id string) (*FollowResponse, error) {
+
+	response, err := c.call_as_user(ctx, "UnfollowList", nil, list_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1378,66 +1740,88 @@ func (c *Client) UnfollowList(list_id string) (*FollowResponse, error) {
 // Returns a list of users who are followers of the specified List.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-follows/api-reference/get-lists-id-followers
-func (c *Client) GetListFollowers(list_id string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "pagination_token",
-		"tweet.fields", "user.fields",
-	}
-
-	route := fmt.Sprintf("lists/%s/followers", list_id)
+func (c *Client) GetListFollowers(list_id string, params Parameters) (*UsersResponse, error) {
+	return c.GetListFollowersCtx(context.Background(), list_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetListFollowers, but the request is bound to ctx.
+func (c *Client) GetListFollowersCtx(ctx context.Context, list_id string, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetListFollowers", params, list_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: list_id, Params: params}
-	users := &UsersResponse{Caller: c.GetListFollowers, CallerData: caller_data}
+	users := &UsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*UsersResponse, error) {
+		return c.GetListFollowersCtx(ctx, list_id, params)
+	})
 
 	return users.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetListFollowers.
+func (c *Client) GetListFollowersIterator(list_id string, params Parameters) *Iterator[UsersResponse, entities.User] {
+	return new_iterator[UsersResponse, entities.User](func(ctx context.Context) (*UsersResponse, error) {
+		return c.GetListFollowersCtx(ctx, list_id, params)
+	})
+}
+
 // Returns all Lists a specified user follows.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-follows/api-reference/get-users-id-followed_lists
-func (c *Client) GetFollowedLists(user_id string, params Map) (*ListsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "pagination_token",
-		"list.fields", "user.fields",
-	}
-
-	route := fmt.Sprintf("users/%s/followed_lists", user_id)
+func (c *Client) GetFollowedLists(user_id string, params Parameters) (*ListsResponse, error) {
+	return c.GetFollowedListsCtx(context.Background(), user_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetFollowedLists, but the request is bound to ctx.
+func (c *Client) GetFollowedListsCtx(ctx context.Context, user_id string, options Parameters) (*ListsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetFollowedLists", params, user_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	lists := &ListsResponse{Caller: c.GetFollowedLists, CallerData: caller_data}
+	lists := &ListsResponse{ctx: ctx}
+	lists.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*ListsResponse, error) {
+		return c.GetFollowedListsCtx(ctx, user_id, params)
+	})
 
 	return lists.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetFollowedLists.
+func (c *Client) GetFollowedListsIterator(user_id string, params Parameters) *Iterator[ListsResponse, List] {
+	return new_iterator[ListsResponse, List](func(ctx context.Context) (*ListsResponse, error) {
+		return c.GetFollowedListsCtx(ctx, user_id, params)
+	})
+}
+
 // ** List lookup ** //
 
 // Returns the details of a specified List.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-lookup/api-reference/get-lists-id
-func (c *Client) GetList(list_id string, params Map) (*ListResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "list.fields", "user.fields",
+func (c *Client) GetList(list_id string, params Parameters) (*ListResponse, error) {
+	return c.GetListCtx(context.Background(), list_id, params)
+}
+
+// Same as GetList, but the request is bound to ctx.
+func (c *Client) GetListCtx(ctx context.Context, list_id string, options Parameters) (*ListResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
-	route := fmt.Sprintf("lists/%s", list_id)
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+
+	response, err := c.call(ctx, "GetList", params, list_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1447,46 +1831,53 @@ func (c *Client) GetList(list_id string, params Map) (*ListResponse, error) {
 // Returns all Lists owned by the specified user.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-lookup/api-reference/get-users-id-owned_lists
-func (c *Client) GetOwnedLists(user_id string, params Map) (*ListsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "pagination_token",
-		"list.fields", "user.fields",
-	}
-
-	route := fmt.Sprintf("users/%s/owned_lists", user_id)
+func (c *Client) GetOwnedLists(user_id string, params Parameters) (*ListsResponse, error) {
+	return c.GetOwnedListsCtx(context.Background(), user_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetOwnedLists, but the request is bound to ctx.
+func (c *Client) GetOwnedListsCtx(ctx context.Context, user_id string, options Parameters) (*ListsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetOwnedLists", params, user_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	lists := &ListsResponse{Caller: c.GetOwnedLists, CallerData: caller_data}
+	lists := &ListsResponse{ctx: ctx}
+	lists.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*ListsResponse, error) {
+		return c.GetOwnedListsCtx(ctx, user_id, params)
+	})
 
 	return lists.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetOwnedLists.
+func (c *Client) GetOwnedListsIterator(user_id string, params Parameters) *Iterator[ListsResponse, List] {
+	return new_iterator[ListsResponse, List](func(ctx context.Context) (*ListsResponse, error) {
+		return c.GetOwnedListsCtx(ctx, user_id, params)
+	})
+}
+
 // ** List members ** //
 
 // Enables the authenticated user to add a member to a List they own.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-members/api-reference/post-lists-id-members
 func (c *Client) AddListMemeber(list_id, user_id string) (*ListMemberResponse, error) {
+	return c.AddListMemeberCtx(context.Background(), list_id, user_id)
+}
+
+// Same as AddListMemeber, but the request is bound to ctx.
+func (c *Client) AddListMemeberCtx(ctx context.Context, list_id, user_id string) (*ListMemberResponse, error) {
 	data := Map{
 		"user_id": user_id,
 	}
 
-	route := fmt.Sprintf("lists/%s/members", list_id)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call(ctx, "AddListMemeber", data, list_id)
 
 	if err != nil {
 		return nil, err
@@ -1499,9 +1890,13 @@ func (c *Client) AddListMemeber(list_id, user_id string) (*ListMemberResponse, e
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-members/api-reference/delete-lists-id-members-user_id
 func (c *Client) RemoveListMember(list_id, user_id string) (*ListMemberResponse, error) {
-	route := fmt.Sprintf("lists/%s/members/%s", list_id, user_id)
+	return c.RemoveListMemberCtx(context.Background(), list_id, user_id)
+}
+
+// Same as RemoveListMember, but the request is bound to ctx.
+func (c *Client) RemoveListMemberCtx(ctx context.Context, list_id, user_id string) (*ListMemberResponse, error) {
 
-	response, err := c.delete_request(route)
+	response, err := c.call(ctx, "RemoveListMember", nil, list_id, user_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1512,74 +1907,93 @@ func (c *Client) RemoveListMember(list_id, user_id string) (*ListMemberResponse,
 // Returns a list of users who are members of the specified List.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-members/api-reference/get-lists-id-members
-func (c *Client) GetListMembers(list_id string, params Map) (*UsersResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "pagination_token",
-		"tweet.fields", "user.fields",
-	}
-
-	route := fmt.Sprintf("lists/%s/members", list_id)
+func (c *Client) GetListMembers(list_id string, params Parameters) (*UsersResponse, error) {
+	return c.GetListMembersCtx(context.Background(), list_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetListMembers, but the request is bound to ctx.
+func (c *Client) GetListMembersCtx(ctx context.Context, list_id string, options Parameters) (*UsersResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetListMembers", params, list_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: list_id, Params: params}
-	users := &UsersResponse{Caller: c.GetListMembers, CallerData: caller_data}
+	users := &UsersResponse{ctx: ctx}
+	users.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*UsersResponse, error) {
+		return c.GetListMembersCtx(ctx, list_id, params)
+	})
 
 	return users.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetListMembers.
+func (c *Client) GetListMembersIterator(list_id string, params Parameters) *Iterator[UsersResponse, entities.User] {
+	return new_iterator[UsersResponse, entities.User](func(ctx context.Context) (*UsersResponse, error) {
+		return c.GetListMembersCtx(ctx, list_id, params)
+	})
+}
+
 // Returns all Lists a specified user is a member of.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/list-members/api-reference/get-users-id-list_memberships
-func (c *Client) GetListMemberships(user_id string, params Map) (*ListsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "pagination_token",
-		"list.fields", "user.fields",
-	}
-
-	route := fmt.Sprintf("users/%s/list_memberships", user_id)
+func (c *Client) GetListMemberships(user_id string, params Parameters) (*ListsResponse, error) {
+	return c.GetListMembershipsCtx(context.Background(), user_id, params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetListMemberships, but the request is bound to ctx.
+func (c *Client) GetListMembershipsCtx(ctx context.Context, user_id string, options Parameters) (*ListsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, c.oauth_type, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetListMemberships", params, user_id)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: user_id, Params: params}
-	lists := &ListsResponse{Caller: c.GetListMemberships, CallerData: caller_data}
+	lists := &ListsResponse{ctx: ctx}
+	lists.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*ListsResponse, error) {
+		return c.GetListMembershipsCtx(ctx, user_id, params)
+	})
 
 	return lists.Parse(response)
 }
 
+// Returns an Iterator over the pages or the items of GetListMemberships.
+func (c *Client) GetListMembershipsIterator(user_id string, params Parameters) *Iterator[ListsResponse, List] {
+	return new_iterator[ListsResponse, List](func(ctx context.Context) (*ListsResponse, error) {
+		return c.GetListMembershipsCtx(ctx, user_id, params)
+	})
+}
+
 // ** Manage Lists ** //
 
 // Enables the authenticated user to create a List.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/post-lists
-func (c *Client) CreateList(name string, description string, private bool, params Map) (*ListResponse, error) {
-	data := Map{
-		"name":        name,
-		"description": description,
-		"private":     private,
+func (c *Client) CreateList(name string, description string, private bool, params Parameters) (*ListResponse, error) {
+	return c.CreateListCtx(context.Background(), name, description, private, params)
+}
+
+// Same as CreateList, but the request is bound to ctx.
+func (c *Client) CreateListCtx(ctx context.Context, name string, description string, private bool, options Parameters) (*ListResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	route := "lists"
+	data := params
+	data["name"] = name
+	data["description"] = description
+	data["private"] = private
 
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call(ctx, "CreateList", data)
 
 	if err != nil {
 		return nil, err
@@ -1592,16 +2006,23 @@ func (c *Client) CreateList(name string, description string, private bool, param
 // specified List that they own.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/put-lists-id
-func (c *Client) UpdateList(list_id string, name string, description string, private bool, params Map) (*UpdateListResponse, error) {
-	data := Map{
-		"name":        name,
-		"description": description,
-		"private":     private,
+func (c *Client) UpdateList(list_id string, name string, description string, private bool, params Parameters) (*UpdateListResponse, error) {
+	return c.UpdateListCtx(context.Background(), list_id, name, description, private, params)
+}
+
+// Same as UpdateList, but the request is bound to ctx.
+func (c *Client) UpdateListCtx(ctx context.Context, list_id string, name string, description string, private bool, options Parameters) (*UpdateListResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	route := fmt.Sprintf("lists/%s", list_id)
+	data := params
+	data["name"] = name
+	data["description"] = description
+	data["private"] = private
 
-	response, err := c.request("PUT", route, data)
+	response, err := c.call(ctx, "UpdateList", data, list_id)
 
 	if err != nil {
 		return nil, err
@@ -1614,9 +2035,13 @@ func (c *Client) UpdateList(list_id string, name string, description string, pri
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/delete-lists-id
 func (c *Client) DeleteList(list_id string) (*DeleteResponse, error) {
-	route := fmt.Sprintf("lists/%s", list_id)
+	return c.DeleteListCtx(context.Background(), list_id)
+}
+
+// Same as DeleteList, but the request is bound to ctx.
+func (c *Client) DeleteListCtx(ctx context.Context, list_id string) (*DeleteResponse, error) {
 
-	response, err := c.delete_request(route)
+	response, err := c.call(ctx, "DeleteList", nil, list_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1630,17 +2055,16 @@ func (c *Client) DeleteList(list_id string) (*DeleteResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/pinned-lists/api-reference/post-users-id-pinned-lists
 func (c *Client) PinList(list_id string) (*PinResponse, error) {
+	return c.PinListCtx(context.Background(), list_id)
+}
+
+// Same as PinList, but the request is bound to ctx.
+func (c *Client) PinListCtx(ctx context.Context, list_id string) (*PinResponse, error) {
 	data := Map{
 		"list_id": list_id,
 	}
 
-	route := fmt.Sprintf("users/%s/pinned_lists", c.userID)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "PinList", data)
 
 	if err != nil {
 		return nil, err
@@ -1653,9 +2077,13 @@ func (c *Client) PinList(list_id string) (*PinResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/pinned-lists/api-reference/delete-users-id-pinned-lists-list_id
 func (c *Client) UnpinList(list_id string) (*PinResponse, error) {
-	route := fmt.Sprintf("users/%s/pinned_lists/%s", c.userID, list_id)
+	return c.UnpinListCtx(context.Background(), list_id)
+}
 
-	response, err := c.delete_request(route)
+// Same as UnpinList, but the request is bound to ctx.
+func (c *Client) UnpinListCtx(ctx context.Context, list_id string) (*PinResponse, error) {
+
+	response, err := c.call_as_user(ctx, "UnpinList", nil, list_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1666,12 +2094,18 @@ func (c *Client) UnpinList(list_id string) (*PinResponse, error) {
 // Returns the Lists pinned by a specified user.
 //
 // https://developer.twitter.com/en/docs/twitter-api/lists/pinned-lists/api-reference/get-users-id-pinned_lists
-func (c *Client) GetPinnedLists(params Map) (*ListsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "list.fields", "user.fields",
+func (c *Client) GetPinnedLists(params Parameters) (*ListsResponse, error) {
+	return c.GetPinnedListsCtx(context.Background(), params)
+}
+
+// Same as GetPinnedLists, but the request is bound to ctx.
+func (c *Client) GetPinnedListsCtx(ctx context.Context, options Parameters) (*ListsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
-	route := fmt.Sprintf("users/%s/pinned_lists", c.userID)
-	response, err := c.get_request(route, OAuth_1a, params, endpoint_parameters)
+
+	response, err := c.call_as_user(ctx, "GetPinnedLists", params)
 	if err != nil {
 		return nil, err
 	}
@@ -1690,6 +2124,11 @@ func (c *Client) GetPinnedLists(params Map) (*ListsResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/compliance/batch-compliance/api-reference/post-compliance-jobs
 func (c *Client) CreateComplianceJob(job_type, name, resumable string) (*ComplianceJobResponse, error) {
+	return c.CreateComplianceJobCtx(context.Background(), job_type, name, resumable)
+}
+
+// Same as CreateComplianceJob, but the request is bound to ctx.
+func (c *Client) CreateComplianceJobCtx(ctx context.Context, job_type, name, resumable string) (*ComplianceJobResponse, error) {
 	if job_type != "tweets" && job_type != "users" {
 		return nil, fmt.Errorf("job_type must be either 'tweets' or 'users'")
 	}
@@ -1704,13 +2143,7 @@ func (c *Client) CreateComplianceJob(job_type, name, resumable string) (*Complia
 		data["resumable"] = resumable
 	}
 
-	route := "compliance/jobs"
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call(ctx, "CreateComplianceJob", data)
 
 	if err != nil {
 		return nil, err
@@ -1723,9 +2156,13 @@ func (c *Client) CreateComplianceJob(job_type, name, resumable string) (*Complia
 //
 // https://developer.twitter.com/en/docs/twitter-api/compliance/batch-compliance/api-reference/get-compliance-jobs-id
 func (c *Client) GetComplianceJob(job_id string) (*ComplianceJobResponse, error) {
-	route := fmt.Sprintf("compliance/jobs/%s", job_id)
+	return c.GetComplianceJobCtx(context.Background(), job_id)
+}
 
-	response, err := c.get_request(route, OAuth_2, nil, nil)
+// Same as GetComplianceJob, but the request is bound to ctx.
+func (c *Client) GetComplianceJobCtx(ctx context.Context, job_id string) (*ComplianceJobResponse, error) {
+
+	response, err := c.call(ctx, "GetComplianceJob", nil, job_id)
 
 	if err != nil {
 		return nil, err
@@ -1737,18 +2174,23 @@ func (c *Client) GetComplianceJob(job_id string) (*ComplianceJobResponse, error)
 // Returns a list of recent compliance jobs.
 //
 // https://developer.twitter.com/en/docs/twitter-api/compliance/batch-compliance/api-reference/get-compliance-jobs
-func (c *Client) GetComplianceJobs(job_type string, params Map) (*ComplianceJobsResponse, error) {
-	endpoint_parameters := []string{
-		"type", "status",
+func (c *Client) GetComplianceJobs(job_type string, params Parameters) (*ComplianceJobsResponse, error) {
+	return c.GetComplianceJobsCtx(context.Background(), job_type, params)
+}
+
+// Same as GetComplianceJobs, but the request is bound to ctx.
+func (c *Client) GetComplianceJobsCtx(ctx context.Context, job_type string, options Parameters) (*ComplianceJobsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
+
 	if params == nil {
 		params = Map{}
 	}
 	params["type"] = job_type
 
-	route := "compliance/jobs"
-
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call(ctx, "GetComplianceJobs", params)
 
 	if err != nil {
 		return nil, err
@@ -1764,17 +2206,16 @@ func (c *Client) GetComplianceJobs(job_type string, params Map) (*ComplianceJobs
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/bookmarks/api-reference/post-users-id-bookmarks
 func (c *Client) BookmarkTweet(tweet_id string) (*BookmarkResponse, error) {
+	return c.BookmarkTweetCtx(context.Background(), tweet_id)
+}
+
+// Same as BookmarkTweet, but the request is bound to ctx.
+func (c *Client) BookmarkTweetCtx(ctx context.Context, tweet_id string) (*BookmarkResponse, error) {
 	data := Map{
 		"tweet_id": tweet_id,
 	}
 
-	route := fmt.Sprintf("users/%s/bookmarks", c.userID)
-
-	response, err := c.request(
-		"POST",
-		route,
-		data,
-	)
+	response, err := c.call_as_user(ctx, "BookmarkTweet", data)
 
 	if err != nil {
 		return nil, err
@@ -1788,9 +2229,13 @@ func (c *Client) BookmarkTweet(tweet_id string) (*BookmarkResponse, error) {
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/bookmarks/api-reference/delete-users-id-bookmarks-tweet_id
 func (c *Client) RemoveBookmark(tweet_id string) (*BookmarkResponse, error) {
-	route := fmt.Sprintf("users/%s/bookmarks/%s", c.userID, tweet_id)
+	return c.RemoveBookmarkCtx(context.Background(), tweet_id)
+}
+
+// Same as RemoveBookmark, but the request is bound to ctx.
+func (c *Client) RemoveBookmarkCtx(ctx context.Context, tweet_id string) (*BookmarkResponse, error) {
 
-	response, err := c.delete_request(route)
+	response, err := c.call_as_user(ctx, "RemoveBookmark", nil, tweet_id)
 	if err != nil {
 		return nil, err
 	}
@@ -1802,28 +2247,33 @@ func (c *Client) RemoveBookmark(tweet_id string) (*BookmarkResponse, error) {
 // Tweets.
 //
 // https://developer.twitter.com/en/docs/twitter-api/tweets/bookmarks/api-reference/get-users-id-bookmarks
-func (c *Client) GetBookmarkedTweets(params Map) (*BookmarkedTweetsResponse, error) {
-	endpoint_parameters := []string{
-		"expansions", "max_results", "media.fields",
-		"pagination_token", "place.fields", "poll.fields",
-		"tweet.fields", "user.fields",
-	}
-
-	route := fmt.Sprintf("users/%s/bookmarks", c.userID)
+func (c *Client) GetBookmarkedTweets(params Parameters) (*BookmarkedTweetsResponse, error) {
+	return c.GetBookmarkedTweetsCtx(context.Background(), params)
+}
 
-	if params == nil {
-		params = make(Map)
+// Same as GetBookmarkedTweets, but the request is bound to ctx.
+func (c *Client) GetBookmarkedTweetsCtx(ctx context.Context, options Parameters) (*BookmarkedTweetsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := c.get_request(route, OAuth_2, params, endpoint_parameters)
+	response, err := c.call_as_user(ctx, "GetBookmarkedTweets", params)
 	if err != nil {
 		return nil, err
 	}
 
-	caller_data := CallerData{ID: "", Params: params}
-	tweets := &BookmarkedTweetsResponse{Caller: c.GetBookmarkedTweets, CallerData: caller_data}
+	tweets := &BookmarkedTweetsResponse{ctx: ctx}
+	tweets.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*BookmarkedTweetsResponse, error) {
+		return c.GetBookmarkedTweetsCtx(ctx, params)
+	})
 
 	return tweets.Parse(response)
 }
 
-// func QueryMaker() string
+// Returns an Iterator over the pages or the items of GetBookmarkedTweets.
+func (c *Client) GetBookmarkedTweetsIterator(params Parameters) *Iterator[BookmarkedTweetsResponse, entities.Tweet] {
+	return new_iterator[BookmarkedTweetsResponse, entities.Tweet](func(ctx context.Context) (*BookmarkedTweetsResponse, error) {
+		return c.GetBookmarkedTweetsCtx(ctx, params)
+	})
+}
@@ -0,0 +1,156 @@
+package twigo
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Granularity of the buckets of Tweet counts.
+type Granularity string
+
+const (
+	GranularityMinute Granularity = "minute"
+	GranularityHour   Granularity = "hour"
+	GranularityDay    Granularity = "day"
+)
+
+// Length of a bucket of the granularity, zero if it's not a known one.
+func (g Granularity) Duration() time.Duration {
+	switch g {
+	case GranularityMinute:
+		return time.Minute
+	case GranularityHour:
+		return time.Hour
+	case GranularityDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// Number of Tweets created from Start up to End.
+type CountBucket struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	TweetCount int       `json:"tweet_count"`
+}
+
+// Buckets of Tweet counts, ordered by their start time.
+type CountSeries []CountBucket
+
+// Total number of Tweets of all buckets.
+func (s CountSeries) Total() int {
+	total := 0
+	for _, bucket := range s {
+		total += bucket.TweetCount
+	}
+	return total
+}
+
+// Returns the same buckets with their times in location, useful for showing them.
+func (s CountSeries) In(location *time.Location) CountSeries {
+	series := make(CountSeries, 0, len(s))
+	for _, bucket := range s {
+		series = append(series, CountBucket{
+			Start:      bucket.Start.In(location),
+			End:        bucket.End.In(location),
+			TweetCount: bucket.TweetCount,
+		})
+	}
+	return series
+}
+
+// Sums the buckets into bigger ones of the given interval,
+// aligned to the wall clock of location, so daily buckets start at local midnight and weekly ones on Monday.
+//
+// Each bucket is counted in the new bucket containing its start,
+// so interval should be a multiple of the original granularity.
+//
+//	// Hourly counts, summed into days of New York.
+//	location, _ := time.LoadLocation("America/New_York")
+//	daily := counts.Data.Rebucket(24*time.Hour, location)
+func (s CountSeries) Rebucket(interval time.Duration, location *time.Location) CountSeries {
+	if location == nil {
+		location = time.UTC
+	}
+
+	buckets := map[time.Time]*CountBucket{}
+	for _, bucket := range s {
+		start, end := align_bucket(bucket.Start, interval, location)
+		if rebucketed, ok := buckets[start]; ok {
+			rebucketed.TweetCount += bucket.TweetCount
+		} else {
+			buckets[start] = &CountBucket{Start: start, End: end, TweetCount: bucket.TweetCount}
+		}
+	}
+
+	series := make(CountSeries, 0, len(buckets))
+	for _, bucket := range buckets {
+		series = append(series, *bucket)
+	}
+	series.sort_by_start()
+	return series
+}
+
+// Orders the buckets by their start time, in place.
+func (s CountSeries) sort_by_start() {
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].Start.Before(s[j].Start)
+	})
+}
+
+// Returns the bounds of the interval containing t, on the wall clock of location.
+func align_bucket(t time.Time, interval time.Duration, location *time.Location) (time.Time, time.Time) {
+	local := t.In(location)
+	// The wall clock as if it was UTC, so Truncate aligns to local midnights, daylight saving doesn't matter.
+	wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+	start := wall.Truncate(interval)
+	end := start.Add(interval)
+	return from_wall_clock(start, location), from_wall_clock(end, location)
+}
+
+func from_wall_clock(wall time.Time, location *time.Location) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), location)
+}
+
+// Writes the series as CSV, with "start", "end" and "tweet_count" columns, ready for charts and spreadsheets.
+func (s CountSeries) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"start", "end", "tweet_count"}); err != nil {
+		return err
+	}
+	for _, bucket := range s {
+		err := writer.Write([]string{
+			bucket.Start.Format(time.RFC3339),
+			bucket.End.Format(time.RFC3339),
+			strconv.Itoa(bucket.TweetCount),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Returns the counts of all pages of GetAllTweetsCount as a single series.
+//
+// params: Same as GetAllTweetsCount, like a *CountsOptions with a granularity.
+func (c *Client) GetAllTweetsCountSeries(query string, params Parameters) (CountSeries, error) {
+	return c.GetAllTweetsCountSeriesCtx(context.Background(), query, params)
+}
+
+// Same as GetAllTweetsCountSeries, but the requests are bound to ctx.
+func (c *Client) GetAllTweetsCountSeriesCtx(ctx context.Context, query string, params Parameters) (CountSeries, error) {
+	series := CountSeries{}
+	it := c.GetAllTweetsCountIterator(query, params)
+	for it.NextPage(ctx) {
+		series = append(series, it.Page().Data...)
+	}
+	// Pages go back in time, and the buckets of a page may not be ordered either.
+	series.sort_by_start()
+	return series, it.Err()
+}
@@ -0,0 +1,85 @@
+package twigo
+
+import (
+	"testing"
+	"time"
+)
+
+// Buckets of the given interval from start, with the given counts.
+func test_series(start time.Time, interval time.Duration, counts ...int) CountSeries {
+	series := make(CountSeries, 0, len(counts))
+	for i, count := range counts {
+		bucket_start := start.Add(time.Duration(i) * interval)
+		series = append(series, CountBucket{Start: bucket_start, End: bucket_start.Add(interval), TweetCount: count})
+	}
+	return series
+}
+
+func TestRebucket(t *testing.T) {
+	// A Monday.
+	start := time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC)
+	plus_two := time.FixedZone("UTC+2", 2*60*60)
+	shuffled := test_series(start, time.Hour, 1, 2, 3, 4)
+	shuffled[0], shuffled[3] = shuffled[3], shuffled[0]
+
+	tests := []struct {
+		name     string
+		series   CountSeries
+		interval time.Duration
+		location *time.Location
+		want     CountSeries
+	}{
+		{
+			name:     "hours into days",
+			series:   test_series(start, time.Hour, make_counts(48, 1)...),
+			interval: 24 * time.Hour,
+			want:     test_series(start, 24*time.Hour, 24, 24),
+		},
+		{
+			name:     "unsorted buckets",
+			series:   shuffled,
+			interval: 2 * time.Hour,
+			location: time.UTC,
+			want:     test_series(start, 2*time.Hour, 3, 7),
+		},
+		{
+			name:     "days of another time zone",
+			series:   test_series(start, time.Hour, make_counts(24, 1)...),
+			interval: 24 * time.Hour,
+			location: plus_two,
+			want:     test_series(start.Add(-2*time.Hour), 24*time.Hour, 22, 2),
+		},
+		{
+			name:     "days into weeks starting on Monday",
+			series:   test_series(start.Add(-24*time.Hour), 24*time.Hour, 1, 2, 3),
+			interval: 7 * 24 * time.Hour,
+			want:     test_series(start.Add(-7*24*time.Hour), 7*24*time.Hour, 1, 5),
+		},
+		{
+			name:     "empty series",
+			series:   CountSeries{},
+			interval: time.Hour,
+			want:     CountSeries{},
+		},
+	}
+	for _, test := range tests {
+		got := test.series.Rebucket(test.interval, test.location)
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %d buckets %v, want %v", test.name, len(got), got, test.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Start.Equal(test.want[i].Start) || !got[i].End.Equal(test.want[i].End) || got[i].TweetCount != test.want[i].TweetCount {
+				t.Errorf("%s: bucket %d = %+v, want %+v", test.name, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func make_counts(n, count int) []int {
+	counts := make([]int, n)
+	for i := range counts {
+		counts[i] = count
+	}
+	return counts
+}
@@ -0,0 +1,237 @@
+package twigo
+
+import (
+	"context"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// ** Direct Messages ** //
+
+// Returns the Direct Message events of all conversations of the authenticated user,
+// from the last 30 days, the most recent first.
+//
+// Parameters
+//
+// params: Either a *DMEventsOptions, or a Map of the raw query parameters:
+// 	"dm_event.fields", "event_types", "expansions", "media.fields", "tweet.fields", "user.fields",
+// 	"max_results", "pagination_token"
+// Expand "sender_id", "participant_ids" and "attachments.media_keys" to get the users and the media in the includes.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/direct-messages/lookup/api-reference/get-dm_events
+func (c *Client) GetDMEvents(params Parameters) (*DMEventsResponse, error) {
+	return c.GetDMEventsCtx(context.Background(), params)
+}
+
+// Same as GetDMEvents, but the request is bound to ctx.
+func (c *Client) GetDMEventsCtx(ctx context.Context, options Parameters) (*DMEventsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.call(ctx, "GetDMEvents", params)
+	if err != nil {
+		return nil, err
+	}
+
+	events := &DMEventsResponse{ctx: ctx}
+	events.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*DMEventsResponse, error) {
+		return c.GetDMEventsCtx(ctx, params)
+	})
+
+	return events.Parse(response)
+}
+
+// Returns an Iterator over the pages or the items of GetDMEvents.
+func (c *Client) GetDMEventsIterator(params Parameters) *Iterator[DMEventsResponse, entities.DMEvent] {
+	return new_iterator[DMEventsResponse, entities.DMEvent](func(ctx context.Context) (*DMEventsResponse, error) {
+		return c.GetDMEventsCtx(ctx, params)
+	})
+}
+
+// Returns the Direct Message events of a conversation, one-to-one or group, from the last 30 days.
+//
+// Parameters
+//
+// dm_conversation_id: ID of the conversation, like the DMConversationID of its events.
+//
+// params: Same as GetDMEvents.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/direct-messages/lookup/api-reference/get-dm_conversations-dm_conversation_id-dm_events
+func (c *Client) GetConversationDMEvents(dm_conversation_id string, params Parameters) (*DMEventsResponse, error) {
+	return c.GetConversationDMEventsCtx(context.Background(), dm_conversation_id, params)
+}
+
+// Same as GetConversationDMEvents, but the request is bound to ctx.
+func (c *Client) GetConversationDMEventsCtx(ctx context.Context, dm_conversation_id string, options Parameters) (*DMEventsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.call(ctx, "GetConversationDMEvents", params, dm_conversation_id)
+	if err != nil {
+		return nil, err
+	}
+
+	events := &DMEventsResponse{ctx: ctx}
+	events.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*DMEventsResponse, error) {
+		return c.GetConversationDMEventsCtx(ctx, dm_conversation_id, params)
+	})
+
+	return events.Parse(response)
+}
+
+// Returns an Iterator over the pages or the items of GetConversationDMEvents.
+func (c *Client) GetConversationDMEventsIterator(dm_conversation_id string, params Parameters) *Iterator[DMEventsResponse, entities.DMEvent] {
+	return new_iterator[DMEventsResponse, entities.DMEvent](func(ctx context.Context) (*DMEventsResponse, error) {
+		return c.GetConversationDMEventsCtx(ctx, dm_conversation_id, params)
+	})
+}
+
+// Returns the Direct Message events of the one-to-one conversation
+// of the authenticated user with another user, from the last 30 days.
+//
+// Parameters
+//
+// participant_id: ID of the other user of the conversation.
+//
+// params: Same as GetDMEvents.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/direct-messages/lookup/api-reference/get-dm_conversations-with-participant_id-dm_events
+func (c *Client) GetParticipantDMEvents(participant_id string, params Parameters) (*DMEventsResponse, error) {
+	return c.GetParticipantDMEventsCtx(context.Background(), participant_id, params)
+}
+
+// Same as GetParticipantDMEvents, but the request is bound to ctx.
+func (c *Client) GetParticipantDMEventsCtx(ctx context.Context, participant_id string, options Parameters) (*DMEventsResponse, error) {
+	params, err := to_map(options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.call(ctx, "GetParticipantDMEvents", params, participant_id)
+	if err != nil {
+		return nil, err
+	}
+
+	events := &DMEventsResponse{ctx: ctx}
+	events.fetch = fetcher(params, "pagination_token", func(ctx context.Context, params Map) (*DMEventsResponse, error) {
+		return c.GetParticipantDMEventsCtx(ctx, participant_id, params)
+	})
+
+	return events.Parse(response)
+}
+
+// Returns an Iterator over the pages or the items of GetParticipantDMEvents.
+func (c *Client) GetParticipantDMEventsIterator(participant_id string, params Parameters) *Iterator[DMEventsResponse, entities.DMEvent] {
+	return new_iterator[DMEventsResponse, entities.DMEvent](func(ctx context.Context) (*DMEventsResponse, error) {
+		return c.GetParticipantDMEventsCtx(ctx, participant_id, params)
+	})
+}
+
+// Sends a Direct Message to a user, in their one-to-one conversation with the authenticated user,
+// the conversation is created if it doesn't exist.
+//
+// Parameters
+//
+// participant_id: ID of the user who receives the message.
+//
+// message: The text and the attachment of the message, for example:
+// 	client.SendDMToParticipant(user_id, &twigo.DirectMessage{Text: "Hi!"})
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/direct-messages/manage/api-reference/post-dm_conversations-with-participant_id-messages
+func (c *Client) SendDMToParticipant(participant_id string, message *DirectMessage) (*SendDMResponse, error) {
+	return c.SendDMToParticipantCtx(context.Background(), participant_id, message)
+}
+
+// Same as SendDMToParticipant, but the request is bound to ctx.
+func (c *Client) SendDMToParticipantCtx(ctx context.Context, participant_id string, message *DirectMessage) (*SendDMResponse, error) {
+	data, err := message.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.call(ctx, "SendDMToParticipant", data, participant_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&SendDMResponse{}).Parse(response)
+}
+
+// Sends a Direct Message to an existing conversation, one-to-one or group.
+//
+// Parameters
+//
+// dm_conversation_id: ID of the conversation.
+//
+// message: The text and the attachment of the message.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/direct-messages/manage/api-reference/post-dm_conversations-dm_conversation_id-messages
+func (c *Client) SendDMToConversation(dm_conversation_id string, message *DirectMessage) (*SendDMResponse, error) {
+	return c.SendDMToConversationCtx(context.Background(), dm_conversation_id, message)
+}
+
+// Same as SendDMToConversation, but the request is bound to ctx.
+func (c *Client) SendDMToConversationCtx(ctx context.Context, dm_conversation_id string, message *DirectMessage) (*SendDMResponse, error) {
+	data, err := message.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.call(ctx, "SendDMToConversation", data, dm_conversation_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&SendDMResponse{}).Parse(response)
+}
+
+// Creates a group conversation of the authenticated user and participants, starting with message,
+// the ID of the new conversation is in the DMConversationID of the response.
+//
+// Parameters
+//
+// participant_ids: IDs of the other users of the conversation.
+//
+// message: The first message of the conversation.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/direct-messages/manage/api-reference/post-dm_conversations
+func (c *Client) CreateDMConversation(participant_ids []string, message *DirectMessage) (*SendDMResponse, error) {
+	return c.CreateDMConversationCtx(context.Background(), participant_ids, message)
+}
+
+// Same as CreateDMConversation, but the request is bound to ctx.
+func (c *Client) CreateDMConversationCtx(ctx context.Context, participant_ids []string, message *DirectMessage) (*SendDMResponse, error) {
+	message_data, err := message.ToMap()
+	if err != nil {
+		return nil, err
+	}
+
+	data := Map{
+		"conversation_type": "Group",
+		"participant_ids":   participant_ids,
+		"message":           message_data,
+	}
+
+	response, err := c.call(ctx, "CreateDMConversation", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&SendDMResponse{}).Parse(response)
+}
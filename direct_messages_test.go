@@ -0,0 +1,162 @@
+package twigo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// Records the requests it gets, and answers them with body.
+type fake_dm_server struct {
+	body     string
+	requests []string
+	bodies   []Map
+	queries  []string
+}
+
+func (f *fake_dm_server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	f.queries = append(f.queries, r.URL.RawQuery)
+	body := Map{}
+	json.NewDecoder(r.Body).Decode(&body)
+	f.bodies = append(f.bodies, body)
+	if r.Method == "POST" {
+		w.WriteHeader(http.StatusCreated)
+	}
+	w.Write([]byte(f.body))
+}
+
+func new_test_dm_client(t *testing.T, body string) (*Client, *fake_dm_server) {
+	fake := &fake_dm_server{body: body}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client, _ := NewClient(
+		&Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret"},
+		WithBaseURL(server.URL),
+	)
+	return client, fake
+}
+
+func TestSendDM(t *testing.T) {
+	client, fake := new_test_dm_client(t, `{"data":{"dm_conversation_id":"42-2","dm_event_id":"100"}}`)
+	message := &DirectMessage{Text: "Hi!", Attachments: []DMAttachment{{MediaID: "9"}}}
+
+	response, err := client.SendDMToParticipant("2", message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Data.DMConversationID != "42-2" || response.Data.DMEventID != "100" {
+		t.Errorf("response = %+v, want conversation 42-2 and event 100", response.Data)
+	}
+	if _, err := client.SendDMToConversation("42-2", &DirectMessage{Text: "Again"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateDMConversation([]string{"2", "3"}, &DirectMessage{Text: "Hi all"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "POST /2/dm_conversations/with/2/messages; POST /2/dm_conversations/42-2/messages; POST /2/dm_conversations"
+	if got := strings.Join(fake.requests, "; "); got != want {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+	bodies := []string{}
+	for _, body := range fake.bodies {
+		data, _ := json.Marshal(body)
+		bodies = append(bodies, string(data))
+	}
+	want_bodies := []string{
+		`{"attachments":[{"media_id":"9"}],"text":"Hi!"}`,
+		`{"text":"Again"}`,
+		`{"conversation_type":"Group","message":{"text":"Hi all"},"participant_ids":["2","3"]}`,
+	}
+	if strings.Join(bodies, "\n") != strings.Join(want_bodies, "\n") {
+		t.Errorf("bodies =\n%s\nwant\n%s", strings.Join(bodies, "\n"), strings.Join(want_bodies, "\n"))
+	}
+}
+
+func TestSendDMInvalid(t *testing.T) {
+	client, fake := new_test_dm_client(t, `{}`)
+	messages := []*DirectMessage{
+		nil,
+		{},
+		{Text: "Hi!", Attachments: []DMAttachment{{MediaID: "9"}, {MediaID: "10"}}},
+		{Attachments: []DMAttachment{{}}},
+	}
+	for _, message := range messages {
+		if _, err := client.SendDMToParticipant("2", message); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("error of %+v = %v, want %v", message, err, ErrInvalidParams)
+		}
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("requests = %v, want none", fake.requests)
+	}
+}
+
+func TestGetDMEvents(t *testing.T) {
+	client, fake := new_test_dm_client(t, `{
+		"data": [
+			{"id": "100", "event_type": "MessageCreate", "text": "Hi!", "sender_id": "2", "dm_conversation_id": "42-2",
+				"created_at": "2022-10-10T10:00:00.000Z", "attachments": {"media_keys": ["3_1"]}},
+			{"id": "101", "event_type": "ParticipantsJoin", "dm_conversation_id": "1000", "participant_ids": ["3"]}
+		],
+		"meta": {"result_count": 2}
+	}`)
+
+	response, err := client.GetDMEvents(&DMEventsOptions{EventTypes: []string{entities.DMEventMessageCreate, entities.DMEventParticipantsJoin}, MaxResults: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.requests[0] != "GET /2/dm_events" || !strings.Contains(fake.queries[0], "event_types=MessageCreate%2CParticipantsJoin") || !strings.Contains(fake.queries[0], "max_results=50") {
+		t.Errorf("request = %s?%s", fake.requests[0], fake.queries[0])
+	}
+	if len(response.Data) != 2 {
+		t.Fatalf("events = %+v, want 2 events", response.Data)
+	}
+	message, join := response.Data[0], response.Data[1]
+	if message.Text != "Hi!" || message.SenderID != "2" || message.DMConversationID != "42-2" || len(message.Attachments.MediaKeys) != 1 ||
+		!message.CreatedAt.Equal(time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("message = %+v", message)
+	}
+	if join.EventType != entities.DMEventParticipantsJoin || len(join.ParticipantIDs) != 1 || !join.CreatedAt.IsZero() {
+		t.Errorf("join event = %+v", join)
+	}
+
+	client.GetConversationDMEvents("1000", nil)
+	client.GetParticipantDMEvents("2", nil)
+	if got := strings.Join(fake.requests[1:], "; "); got != "GET /2/dm_conversations/1000/dm_events; GET /2/dm_conversations/with/2/dm_events" {
+		t.Errorf("requests = %q", got)
+	}
+
+	if _, err := client.GetDMEvents(&DMEventsOptions{EventTypes: []string{"MessageDelete"}}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error of an unknown event type = %v, want %v", err, ErrInvalidParams)
+	}
+}
+
+func TestGetDMEventsIterator(t *testing.T) {
+	pages := &fake_pages{ids: []string{"1", "2", "3"}, page_size: 2}
+	server := httptest.NewServer(pages)
+	defer server.Close()
+	client, _ := NewClient(
+		&Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret"},
+		WithBaseURL(server.URL),
+	)
+
+	it := client.GetParticipantDMEventsIterator("2", nil)
+	ids := []string{}
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, " ") != "1 2 3" || strings.Join(pages.tokens, ",") != ",2" {
+		t.Errorf("events = %v with tokens %v, want 1 2 3 in 2 pages", ids, pages.tokens)
+	}
+}
@@ -0,0 +1,690 @@
+package twigo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// How the pages of an endpoint are requested.
+type PaginationStyle string
+
+const (
+	// The endpoint returns a single page.
+	NoPagination PaginationStyle = ""
+	// The token of the next page is sent as "pagination_token", like timelines and lookups of followers.
+	PaginationTokenStyle PaginationStyle = "pagination_token"
+	// The token of the next page is sent as "next_token", like search and counts endpoints.
+	NextTokenStyle PaginationStyle = "next_token"
+)
+
+// Describes an endpoint of the API, the client builds its requests from these descriptions,
+// so they can be used to know what a method sends, which params it accepts, and which auth it needs.
+type Endpoint struct {
+	// Name of the method of the Client calling the endpoint, like "GetUserTweets".
+	Name string
+	// HTTP method, like "GET".
+	Method string
+	// Path relative to the "2/" prefix, with placeholders like "users/:id/tweets".
+	Path string
+	// Params sent in the query string.
+	Query []string
+	// Params sent in the JSON body, only POST and PUT endpoints have them.
+	Body []string
+	// Params that must be set, from either the query or the body.
+	Required []string
+	// Auth types the endpoint accepts.
+	Auth []OAuthType
+	// Auth type used when the client has credentials for more than one of them.
+	DefaultAuth OAuthType
+	// Requests per 15 minutes with app-only auth, zero if it's not supported.
+	AppRateLimit int
+	// Requests per 15 minutes per user with user context auth, zero if it's not supported.
+	UserRateLimit int
+	Pagination    PaginationStyle
+}
+
+// Whether or not the endpoint accepts the auth type.
+func (e Endpoint) Supports(oauth_type OAuthType) bool {
+	for _, auth := range e.Auth {
+		if auth == oauth_type {
+			return true
+		}
+	}
+	return false
+}
+
+// Requests per 15 minutes with the auth type, zero if it's not supported.
+func (e Endpoint) RateLimit(oauth_type OAuthType) int {
+	if oauth_type == OAuth_2 {
+		return e.AppRateLimit
+	}
+	return e.UserRateLimit
+}
+
+// Returns the name the query param is sent with, if the endpoint accepts it,
+// "tweet_fields" is accepted as "tweet.fields" too.
+func (e Endpoint) query_param(name string) (string, bool) {
+	for _, param := range e.Query {
+		if param == name || strings.Replace(param, ".", "_", 1) == name {
+			return param, true
+		}
+	}
+	return "", false
+}
+
+func (e Endpoint) body_param(name string) bool {
+	for _, param := range e.Body {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Splits params into the query and the body of a request,
+// params the endpoint doesn't accept, and missing required ones are errors.
+func (e Endpoint) split_params(params Map) (query, body Map, err error) {
+	query, body = Map{}, Map{}
+	for name, value := range params {
+		if param, ok := e.query_param(name); ok {
+			query[param] = value
+		} else if e.body_param(name) {
+			body[name] = value
+		} else {
+			return nil, nil, invalid_params("%s doesn't accept %q", e.Name, name)
+		}
+	}
+
+	for _, name := range e.Required {
+		if is_empty(query[name]) && is_empty(body[name]) {
+			return nil, nil, invalid_params("%s requires %q", e.Name, name)
+		}
+	}
+	return query, body, nil
+}
+
+// Whether or not a param value is nil, or an empty string, slice or map.
+func is_empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Fills the placeholders of the path in order, like "users/:id/tweets" with "2244994945".
+func (e Endpoint) route(path_params []string) (string, error) {
+	segments := strings.Split(e.Path, "/")
+	filled := 0
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if filled == len(path_params) {
+			return "", invalid_params("%s needs a value for %s", e.Name, segment)
+		}
+		if path_params[filled] == "" {
+			return "", invalid_params("%s: %s can't be empty", e.Name, segment)
+		}
+		segments[i] = path_params[filled]
+		filled++
+	}
+	if filled != len(path_params) {
+		return "", fmt.Errorf("twigo: %s takes %d path params, not %d", e.Name, filled, len(path_params))
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// Returns a copy which doesn't share its slices with the registry.
+func (e Endpoint) clone() Endpoint {
+	e.Query = append([]string(nil), e.Query...)
+	e.Body = append([]string(nil), e.Body...)
+	e.Required = append([]string(nil), e.Required...)
+	e.Auth = append([]OAuthType(nil), e.Auth...)
+	return e
+}
+
+// Returns the endpoints the client knows about, sorted by their names.
+func Endpoints() []Endpoint {
+	endpoints := make([]Endpoint, 0, len(endpoint_registry))
+	for _, endpoint := range endpoint_registry {
+		endpoints = append(endpoints, endpoint.clone())
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+	return endpoints
+}
+
+// Returns the endpoint a method of the Client calls, like LookupEndpoint("GetUserTweets").
+func LookupEndpoint(name string) (Endpoint, bool) {
+	endpoint := find_endpoint(name)
+	if endpoint == nil {
+		return Endpoint{}, false
+	}
+	return endpoint.clone(), true
+}
+
+func find_endpoint(name string) *Endpoint {
+	for i := range endpoint_registry {
+		if endpoint_registry[i].Name == name {
+			return &endpoint_registry[i]
+		}
+	}
+	return nil
+}
+
+// Returns the endpoint with the method whose path template matches route,
+// the most specific template wins, so "users/by" is not mistaken for "users/:id".
+func match_endpoint(method, route string) *Endpoint {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+
+	var best *Endpoint
+	best_score := -1
+	for i := range endpoint_registry {
+		endpoint := &endpoint_registry[i]
+		if endpoint.Method != method {
+			continue
+		}
+		if score, ok := match_template(endpoint.Path, segments); ok && score > best_score {
+			best, best_score = endpoint, score
+		}
+	}
+	return best
+}
+
+var (
+	app_auth         = []OAuthType{OAuth_2}
+	user_auth        = []OAuthType{OAuth_1a, OAuth_2_User}
+	app_user_auth    = []OAuthType{OAuth_2, OAuth_1a, OAuth_2_User}
+	oauth2_user_auth = []OAuthType{OAuth_2_User}
+)
+
+var (
+	tweet_fields_params = []string{
+		"expansions", "media.fields", "place.fields",
+		"poll.fields", "tweet.fields", "user.fields",
+	}
+	user_fields_params  = []string{"expansions", "tweet.fields", "user.fields"}
+	list_fields_params  = []string{"expansions", "list.fields", "user.fields"}
+	space_fields_params = []string{"expansions", "space.fields", "user.fields"}
+	stream_params       = []string{
+		"backfill_minutes", "expansions", "media.fields",
+		"place.fields", "poll.fields", "tweet.fields", "user.fields",
+	}
+	search_params = []string{
+		"query", "start_time", "end_time", "since_id", "until_id",
+		"max_results", "next_token", "sort_order",
+	}
+	counts_params = []string{
+		"query", "start_time", "end_time", "since_id", "until_id", "granularity",
+	}
+	timeline_params = []string{
+		"start_time", "end_time", "since_id", "until_id",
+		"max_results", "pagination_token",
+	}
+	page_params     = []string{"max_results", "pagination_token"}
+	dm_event_params = []string{
+		"dm_event.fields", "event_types", "expansions", "media.fields",
+		"tweet.fields", "user.fields", "max_results", "pagination_token",
+	}
+)
+
+func params_of(groups ...[]string) []string {
+	params := []string{}
+	for _, group := range groups {
+		params = append(params, group...)
+	}
+	return params
+}
+
+// Every endpoint the client calls.
+//
+// https://developer.twitter.com/en/docs/twitter-api/rate-limits
+var endpoint_registry = []Endpoint{
+	// Manage Tweets
+	{
+		Name: "CreateTweet", Method: "POST", Path: "tweets",
+		Body: []string{
+			"text", "direct_message_deep_link", "for_super_followers_only", "geo",
+			"media", "poll", "quote_tweet_id", "reply", "reply_settings",
+		},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 200,
+	},
+	{
+		Name: "DeleteTweet", Method: "DELETE", Path: "tweets/:id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+
+	// Likes
+	{
+		Name: "Like", Method: "POST", Path: "users/:id/likes",
+		Body: []string{"tweet_id"}, Required: []string{"tweet_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "Unlike", Method: "DELETE", Path: "users/:id/likes/:tweet_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "GetLikingUsers", Method: "GET", Path: "tweets/:id/liking_users",
+		Query: params_of(tweet_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 75, UserRateLimit: 75,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetLikedTweets", Method: "GET", Path: "users/:id/liked_tweets",
+		Query: params_of(tweet_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 75, UserRateLimit: 75,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Hide replies
+	{
+		Name: "HideReply", Method: "PUT", Path: "tweets/:id/hidden",
+		Body: []string{"hidden"}, Required: []string{"hidden"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "UnHideReply", Method: "PUT", Path: "tweets/:id/hidden",
+		Body: []string{"hidden"}, Required: []string{"hidden"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+
+	// Retweets
+	{
+		Name: "Retweet", Method: "POST", Path: "users/:id/retweets",
+		Body: []string{"tweet_id"}, Required: []string{"tweet_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "UnRetweet", Method: "DELETE", Path: "users/:id/retweets/:source_tweet_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "GetRetweeters", Method: "GET", Path: "tweets/:id/retweeted_by",
+		Query: params_of(tweet_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 75, UserRateLimit: 75,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Quote Tweets
+	{
+		Name: "GetQuoteTweets", Method: "GET", Path: "tweets/:id/quote_tweets",
+		Query: params_of(tweet_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 75, UserRateLimit: 75,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Search Tweets
+	{
+		Name: "SearchAllTweets", Method: "GET", Path: "tweets/search/all",
+		Query: params_of(search_params, tweet_fields_params), Required: []string{"query"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 300,
+		Pagination: NextTokenStyle,
+	},
+	{
+		Name: "SearchRecentTweets", Method: "GET", Path: "tweets/search/recent",
+		Query: params_of(search_params, tweet_fields_params), Required: []string{"query"},
+		Auth: app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 450, UserRateLimit: 180,
+		Pagination: NextTokenStyle,
+	},
+
+	// Timelines
+	{
+		Name: "GetUserTweets", Method: "GET", Path: "users/:id/tweets",
+		Query: params_of(timeline_params, []string{"exclude"}, tweet_fields_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 1500, UserRateLimit: 900,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetUserMentions", Method: "GET", Path: "users/:id/mentions",
+		Query: params_of(timeline_params, tweet_fields_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 450, UserRateLimit: 180,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetHomeTimeline", Method: "GET", Path: "users/:id/timelines/reverse_chronological",
+		Query: params_of(timeline_params, []string{"exclude"}, tweet_fields_params),
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 180,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Tweet counts
+	{
+		Name: "GetAllTweetsCount", Method: "GET", Path: "tweets/counts/all",
+		Query: params_of(counts_params, []string{"next_token"}), Required: []string{"query"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 300,
+		Pagination: NextTokenStyle,
+	},
+	{
+		Name: "GetRecentTweetsCount", Method: "GET", Path: "tweets/counts/recent",
+		Query: counts_params, Required: []string{"query"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 300,
+	},
+
+	// Tweets lookup
+	{
+		Name: "GetTweet", Method: "GET", Path: "tweets/:id",
+		Query: tweet_fields_params,
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 900,
+	},
+	{
+		Name: "GetTweets", Method: "GET", Path: "tweets",
+		Query: params_of([]string{"ids"}, tweet_fields_params), Required: []string{"ids"},
+		Auth: app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 900,
+	},
+
+	// Blocks
+	{
+		Name: "Block", Method: "POST", Path: "users/:id/blocking",
+		Body: []string{"target_user_id"}, Required: []string{"target_user_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "UnBlock", Method: "DELETE", Path: "users/:id/blocking/:target_user_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "GetBlocked", Method: "GET", Path: "users/:id/blocking",
+		Query: params_of(user_fields_params, page_params),
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 15,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Follows
+	{
+		Name: "FollowUser", Method: "POST", Path: "users/:id/following",
+		Body: []string{"target_user_id"}, Required: []string{"target_user_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "UnfollowUser", Method: "DELETE", Path: "users/:id/following/:target_user_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "GetUserFollowers", Method: "GET", Path: "users/:id/followers",
+		Query: params_of(user_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 15, UserRateLimit: 15,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetUserFollowing", Method: "GET", Path: "users/:id/following",
+		Query: params_of(user_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 15, UserRateLimit: 15,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Mutes
+	{
+		Name: "Mute", Method: "POST", Path: "users/:id/muting",
+		Body: []string{"target_user_id"}, Required: []string{"target_user_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "UnMute", Method: "DELETE", Path: "users/:id/muting/:target_user_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "GetMuted", Method: "GET", Path: "users/:id/muting",
+		Query: params_of(user_fields_params, page_params),
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 15,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Users lookup
+	{
+		Name: "GetMe", Method: "GET", Path: "users/me",
+		Query: user_fields_params,
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 75,
+	},
+	{
+		Name: "GetUserByID", Method: "GET", Path: "users/:id",
+		Query: user_fields_params,
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 900,
+	},
+	{
+		Name: "GetUserByUsername", Method: "GET", Path: "users/by/username/:username",
+		Query: user_fields_params,
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 900,
+	},
+	{
+		Name: "GetUsersByIDs", Method: "GET", Path: "users",
+		Query: params_of([]string{"ids"}, user_fields_params), Required: []string{"ids"},
+		Auth: app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 900,
+	},
+	{
+		Name: "GetUsersByUsernames", Method: "GET", Path: "users/by",
+		Query: params_of([]string{"usernames"}, user_fields_params), Required: []string{"usernames"},
+		Auth: app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 900,
+	},
+
+	// Spaces
+	{
+		Name: "SearchSpaces", Method: "GET", Path: "spaces/search",
+		Query: params_of([]string{"query", "max_results", "state"}, space_fields_params), Required: []string{"query"},
+		Auth: app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 300,
+	},
+	{
+		Name: "GetSpacesBySpaceIDs", Method: "GET", Path: "spaces",
+		Query: params_of([]string{"ids"}, space_fields_params), Required: []string{"ids"},
+		Auth: app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 300,
+	},
+	{
+		Name: "GetSpacesByCreatorIDs", Method: "GET", Path: "spaces/by/creator_ids",
+		Query: params_of([]string{"user_ids"}, space_fields_params), Required: []string{"user_ids"},
+		Auth: app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 300,
+	},
+	{
+		Name: "GetSpace", Method: "GET", Path: "spaces/:id",
+		Query: space_fields_params,
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 300,
+	},
+	{
+		Name: "GetSpaceBuyers", Method: "GET", Path: "spaces/:id/buyers",
+		Query: tweet_fields_params,
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 300,
+	},
+	{
+		Name: "GetSpaceTweets", Method: "GET", Path: "spaces/:id/tweets",
+		Query: tweet_fields_params,
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 300, UserRateLimit: 300,
+	},
+
+	// Lists
+	{
+		Name: "GetListTweets", Method: "GET", Path: "lists/:id/tweets",
+		Query: params_of(user_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 900, UserRateLimit: 900,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "FollowList", Method: "POST", Path: "users/:id/followed_lists",
+		Body: []string{"list_id"}, Required: []string{"list_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "UnfollowList", Method: "DELETE", Path: "users/:id/followed_lists/:list_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "GetListFollowers", Method: "GET", Path: "lists/:id/followers",
+		Query: params_of(user_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 180, UserRateLimit: 180,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetFollowedLists", Method: "GET", Path: "users/:id/followed_lists",
+		Query: params_of(list_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 15, UserRateLimit: 15,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetList", Method: "GET", Path: "lists/:id",
+		Query: list_fields_params,
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 75, UserRateLimit: 75,
+	},
+	{
+		Name: "GetOwnedLists", Method: "GET", Path: "users/:id/owned_lists",
+		Query: params_of(list_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 15, UserRateLimit: 15,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "AddListMemeber", Method: "POST", Path: "lists/:id/members",
+		Body: []string{"user_id"}, Required: []string{"user_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+	},
+	{
+		Name: "RemoveListMember", Method: "DELETE", Path: "lists/:id/members/:user_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+	},
+	{
+		Name: "GetListMembers", Method: "GET", Path: "lists/:id/members",
+		Query: params_of(user_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 900, UserRateLimit: 900,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetListMemberships", Method: "GET", Path: "users/:id/list_memberships",
+		Query: params_of(list_fields_params, page_params),
+		Auth:  app_user_auth, DefaultAuth: OAuth_2, AppRateLimit: 75, UserRateLimit: 75,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "CreateList", Method: "POST", Path: "lists",
+		Body: []string{"name", "description", "private"}, Required: []string{"name"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+	},
+	{
+		Name: "UpdateList", Method: "PUT", Path: "lists/:id",
+		Body: []string{"name", "description", "private"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+	},
+	{
+		Name: "DeleteList", Method: "DELETE", Path: "lists/:id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+	},
+	{
+		Name: "PinList", Method: "POST", Path: "users/:id/pinned_lists",
+		Body: []string{"list_id"}, Required: []string{"list_id"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "UnpinList", Method: "DELETE", Path: "users/:id/pinned_lists/:list_id",
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 50,
+	},
+	{
+		Name: "GetPinnedLists", Method: "GET", Path: "users/:id/pinned_lists",
+		Query: list_fields_params,
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 15,
+	},
+
+	// Compliance
+	{
+		Name: "CreateComplianceJob", Method: "POST", Path: "compliance/jobs",
+		Body: []string{"type", "name", "resumable"}, Required: []string{"type"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 150,
+	},
+	{
+		Name: "GetComplianceJob", Method: "GET", Path: "compliance/jobs/:id",
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 150,
+	},
+	{
+		Name: "GetComplianceJobs", Method: "GET", Path: "compliance/jobs",
+		Query: []string{"type", "status"}, Required: []string{"type"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 150,
+	},
+
+	// Bookmarks
+	{
+		Name: "BookmarkTweet", Method: "POST", Path: "users/:id/bookmarks",
+		Body: []string{"tweet_id"}, Required: []string{"tweet_id"},
+		Auth: oauth2_user_auth, DefaultAuth: OAuth_2_User, UserRateLimit: 50,
+	},
+	{
+		Name: "RemoveBookmark", Method: "DELETE", Path: "users/:id/bookmarks/:tweet_id",
+		Auth: oauth2_user_auth, DefaultAuth: OAuth_2_User, UserRateLimit: 50,
+	},
+	{
+		Name: "GetBookmarkedTweets", Method: "GET", Path: "users/:id/bookmarks",
+		Query: params_of(tweet_fields_params, page_params),
+		Auth:  oauth2_user_auth, DefaultAuth: OAuth_2_User, UserRateLimit: 180,
+		Pagination: PaginationTokenStyle,
+	},
+
+	// Streams
+	{
+		Name: "FilteredStream", Method: "GET", Path: "tweets/search/stream",
+		Query: stream_params,
+		Auth:  app_auth, DefaultAuth: OAuth_2, AppRateLimit: 50,
+	},
+	{
+		Name: "SampleStream", Method: "GET", Path: "tweets/sample/stream",
+		Query: stream_params,
+		Auth:  app_auth, DefaultAuth: OAuth_2, AppRateLimit: 50,
+	},
+	{
+		Name: "Sample10Stream", Method: "GET", Path: "tweets/sample10/stream",
+		Query: params_of([]string{"partition", "start_time", "end_time"}, stream_params), Required: []string{"partition"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 50,
+	},
+	{
+		Name: "GetStreamRules", Method: "GET", Path: "tweets/search/stream/rules",
+		Query: []string{"ids"},
+		Auth:  app_auth, DefaultAuth: OAuth_2, AppRateLimit: 450,
+	},
+	{
+		Name: "AddStreamRules", Method: "POST", Path: "tweets/search/stream/rules",
+		Query: []string{"dry_run"}, Body: []string{"add"}, Required: []string{"add"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 450,
+	},
+	{
+		Name: "DeleteStreamRules", Method: "POST", Path: "tweets/search/stream/rules",
+		Query: []string{"dry_run"}, Body: []string{"delete"}, Required: []string{"delete"},
+		Auth: app_auth, DefaultAuth: OAuth_2, AppRateLimit: 450,
+	},
+
+	// Direct Messages
+	{
+		Name: "GetDMEvents", Method: "GET", Path: "dm_events",
+		Query: dm_event_params,
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetConversationDMEvents", Method: "GET", Path: "dm_conversations/:id/dm_events",
+		Query: dm_event_params,
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "GetParticipantDMEvents", Method: "GET", Path: "dm_conversations/with/:participant_id/dm_events",
+		Query: dm_event_params,
+		Auth:  user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 300,
+		Pagination: PaginationTokenStyle,
+	},
+	{
+		Name: "SendDMToParticipant", Method: "POST", Path: "dm_conversations/with/:participant_id/messages",
+		Body: []string{"text", "attachments"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 200,
+	},
+	{
+		Name: "SendDMToConversation", Method: "POST", Path: "dm_conversations/:id/messages",
+		Body: []string{"text", "attachments"},
+		Auth: user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 200,
+	},
+	{
+		Name: "CreateDMConversation", Method: "POST", Path: "dm_conversations",
+		Body:     []string{"conversation_type", "participant_ids", "message"},
+		Required: []string{"conversation_type", "participant_ids", "message"},
+		Auth:     user_auth, DefaultAuth: OAuth_1a, UserRateLimit: 200,
+	},
+}
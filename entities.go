@@ -23,6 +23,7 @@ type ErrorEntity struct {
 	Message    string                 `json:"message"`
 }
 
+// Deprecated: non-2XX responses are returned as *APIError now.
 type SpecialError struct {
 	Title  string `json:"title"`
 	Type   string `json:"type"`
@@ -77,7 +78,7 @@ type Media struct {
 	PromotedMetrics  map[string]int `json:"promoted_metrics,omitempty"`
 	PublicMetrics    map[string]int `json:"public_metrics,omitempty"`
 	Width            int            `json:"width,omitempty"`
-	AltText          int            `json:"alt_text,omitempty"`
+	AltText          string         `json:"alt_text,omitempty"`
 }
 
 type Poll struct {
@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// Types of the events of a Direct Message conversation.
+const (
+	DMEventMessageCreate     = "MessageCreate"
+	DMEventParticipantsJoin  = "ParticipantsJoin"
+	DMEventParticipantsLeave = "ParticipantsLeave"
+)
+
+// An event of a Direct Message conversation, a message,
+// or participants joining or leaving a group conversation.
+type DMEvent struct {
+	ID string `json:"id"`
+	// One of DMEventMessageCreate, DMEventParticipantsJoin and DMEventParticipantsLeave.
+	EventType string `json:"event_type"`
+	// Only MessageCreate events have text.
+	Text             string    `json:"text,omitempty"`
+	SenderID         string    `json:"sender_id,omitempty"`
+	DMConversationID string    `json:"dm_conversation_id,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	// Only ParticipantsJoin and ParticipantsLeave events have them.
+	ParticipantIDs   []string          `json:"participant_ids,omitempty"`
+	Attachments      DMAttachments     `json:"attachments,omitempty"`
+	ReferencedTweets []ReferencedTweet `json:"referenced_tweets,omitempty"`
+}
+
+// Media of a message, their objects are in the includes of the response
+// when "attachments.media_keys" is expanded.
+type DMAttachments struct {
+	MediaKeys []string `json:"media_keys,omitempty"`
+	CardIDs   []string `json:"card_ids,omitempty"`
+}
@@ -1,8 +1,9 @@
 package entities
 
-import "fmt"
-
-// TODO: Error handeling should be completed.
+import (
+	"fmt"
+	"strings"
+)
 
 type ErrorCode int
 
@@ -17,9 +18,15 @@ func (e ErrorCode) Detail() *ErrorCodeDetail {
 	}
 }
 
+// Whether or not a request failed with this code is worth to be sent again later.
+func (e ErrorCode) Retryable() bool {
+	return e.Detail().Retryable
+}
+
 type ErrorCodeDetail struct {
 	Text        string
 	Description string
+	Retryable   bool
 }
 
 type Non2XXError struct {
@@ -32,6 +39,28 @@ type Non2XXError struct {
 	// RateLimits RateLimits `json:"-"` // It can't be imported because of circular dependency.
 }
 
+func (e *Non2XXError) Error() string {
+	messages := []string{}
+	if e.Title != "" {
+		messages = append(messages, e.Title)
+	}
+	if e.Detail != "" && e.Detail != e.Title {
+		messages = append(messages, e.Detail)
+	}
+	for _, api_error := range e.APIErrors {
+		messages = append(messages, api_error.String())
+	}
+
+	status := e.Status
+	if status == "" {
+		status = fmt.Sprint(e.StatusCode)
+	}
+	if len(messages) == 0 {
+		return status
+	}
+	return fmt.Sprintf("%s: %s", status, strings.Join(messages, "; "))
+}
+
 type ErrorInformation struct {
 	Message    string              `json:"message"`
 	Code       ErrorCode           `json:"code,omitempty"`
@@ -39,6 +68,16 @@ type ErrorInformation struct {
 	Parameters map[string][]string `json:"parameters,omitempty"`
 }
 
+func (e ErrorInformation) String() string {
+	if e.Code == 0 {
+		return e.Message
+	}
+	if e.Message == "" {
+		e.Message = e.Code.Detail().Text
+	}
+	return fmt.Sprintf("%d - %s", int(e.Code), e.Message)
+}
+
 type PartialError struct {
 	ResourceType *string `json:"resource_type"`
 	Field        *string `json:"field"`
@@ -65,7 +104,7 @@ var errorCodeMap map[ErrorCode]ErrorCodeDetail = map[ErrorCode]ErrorCodeDetail{
 	64:  {Text: "Your account is suspended and is not permitted to access this feature.", Description: "Corresponds with HTTP 403. The access token being used belongs to a suspended user."},
 	68:  {Text: "Some actions on this user's Tweet have been disabled by Twitter. The Twitter REST API v1 is no longer active. ", Description: "Corresponds with HTTP 410. The request was made to a retired v1-era URL."},
 	87:  {Text: "Client is not permitted to perform this action.", Description: "Corresponds with HTTP 403. The endpoint called is not a permitted URL."},
-	88:  {Text: "Rate limit exceeded.", Description: "Corresponds with HTTP 429. The request limit for this resource has been reached for the current rate limit window."},
+	88:  {Text: "Rate limit exceeded.", Description: "Corresponds with HTTP 429. The request limit for this resource has been reached for the current rate limit window.", Retryable: true},
 	89:  {Text: "Invalid or expired token.", Description: "Corresponds with HTTP 403. The access token used in the request is incorrect or has expired."},
 	92:  {Text: "SSL is required.", Description: "Corresponds with HTTP 403. Only TLS v1.2 connections are allowed in the API. Update the request to a secure connection. See how to connect using TLS."},
 	93:  {Text: "This App is not allowed to access or delete your Direct Messages.", Description: "Corresponds with HTTP 403. The OAuth token does not provide access to Direct Messages."},
@@ -73,8 +112,8 @@ var errorCodeMap map[ErrorCode]ErrorCodeDetail = map[ErrorCode]ErrorCodeDetail{
 	109: {Text: "The specified user is not found in this list.", Description: "Corresponds with HTTP 404. Not Found."},
 	110: {Text: "The user you are trying to remove from this list is not a member.", Description: "Corresponds with HTTP 400. Bad Request."},
 	120: {Text: "Account update failed: value is too long (maximum is nn characters).", Description: "Corresponds with HTTP 403. Thrown when one of the values passed to the update_profile.json endpoint exceeds the maximum value currently permitted for that field. The error message will specify the allowable maximum number of nn characters."},
-	130: {Text: "Over capacity.", Description: "Corresponds with HTTP 503. Twitter is temporarily over capacity."},
-	131: {Text: "Internal error.", Description: "Corresponds with HTTP 500. An unknown internal error occurred."},
+	130: {Text: "Over capacity.", Description: "Corresponds with HTTP 503. Twitter is temporarily over capacity.", Retryable: true},
+	131: {Text: "Internal error.", Description: "Corresponds with HTTP 500. An unknown internal error occurred.", Retryable: true},
 	135: {Text: "Could not authenticate you.", Description: "Corresponds with HTTP 401. Timestamp out of bounds (often caused by a clock drift when authenticating - check your system clock)."},
 	139: {Text: "You have already favorited this status.", Description: "Corresponds with HTTP 403. A Tweet cannot be favorited (liked) more than once."},
 	144: {Text: "No status found with that ID.", Description: "Corresponds with HTTP 404. The requested Tweet ID is not found (if it existed, it was probably deleted)."},
@@ -0,0 +1,14 @@
+package entities
+
+// A filtered stream rule, ID is set by Twitter when the rule is added.
+type StreamRule struct {
+	ID    string `json:"id,omitempty"`
+	Value string `json:"value"`
+	Tag   string `json:"tag,omitempty"`
+}
+
+// A rule that matched a streamed Tweet.
+type MatchingRule struct {
+	ID  string `json:"id"`
+	Tag string `json:"tag,omitempty"`
+}
@@ -0,0 +1,100 @@
+package twigo
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// Sentinel errors, compare them with errors.Is:
+//
+//	if errors.Is(err, twigo.ErrNotFound) { ... }
+var (
+	ErrBadRequest   = errors.New("twigo: bad request")
+	ErrUnauthorized = errors.New("twigo: unauthorized")
+	ErrForbidden    = errors.New("twigo: forbidden")
+	ErrNotFound     = errors.New("twigo: not found")
+	ErrRateLimited  = errors.New("twigo: rate limited")
+	ErrServerError  = errors.New("twigo: server error")
+)
+
+// APIError is returned by every method when Twitter answers with a non-2XX status code.
+//
+// It carries the status code, the error list of the response body,
+// the rate limits and the raw body, use errors.As to get it:
+//
+//	var api_error *twigo.APIError
+//	if errors.As(err, &api_error) {
+//		fmt.Println(api_error.StatusCode, api_error.RateLimits.ResetTimestamp)
+//	}
+type APIError struct {
+	entities.Non2XXError
+	RateLimits RateLimits
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	return "twigo: " + e.Non2XXError.Error()
+}
+
+// Makes errors.Is(err, ErrNotFound) and other sentinels work.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// Makes errors.As work with *entities.Non2XXError too.
+func (e *APIError) As(target interface{}) bool {
+	if non_2xx_error, ok := target.(**entities.Non2XXError); ok {
+		*non_2xx_error = &e.Non2XXError
+		return true
+	}
+	return false
+}
+
+// Returns the error codes of the response, if there is any.
+func (e *APIError) Codes() []entities.ErrorCode {
+	codes := []entities.ErrorCode{}
+	for _, api_error := range e.APIErrors {
+		if api_error.Code != 0 {
+			codes = append(codes, api_error.Code)
+		}
+	}
+	return codes
+}
+
+// Returns the response itself if the status code is 2XX,
+// otherwise it reads and closes the body and returns an *APIError.
+func check_response(response *http.Response) (*http.Response, error) {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+
+	defer response.Body.Close()
+
+	api_error := &APIError{}
+	api_error.Body, _ = io.ReadAll(response.Body)
+	// The body is not always json (e.g. some proxies), so the decoding error is ignored.
+	json.Unmarshal(api_error.Body, &api_error.Non2XXError)
+	api_error.StatusCode = response.StatusCode
+	api_error.Status = response.Status
+	api_error.RateLimits.Set(response.Header)
+
+	return nil, api_error
+}
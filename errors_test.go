@@ -0,0 +1,91 @@
+package twigo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		// Sentinel the error must match.
+		want       error
+		want_title string
+		want_codes []entities.ErrorCode
+	}{
+		{
+			name:       "problem details",
+			status:     http.StatusNotFound,
+			body:       `{"title":"Not Found Error","detail":"Could not find tweet with id: [1].","type":"https://api.twitter.com/2/problems/resource-not-found"}`,
+			want:       ErrNotFound,
+			want_title: "Not Found Error",
+		},
+		{
+			name:       "error list",
+			status:     http.StatusUnauthorized,
+			body:       `{"errors":[{"code":89,"message":"Invalid or expired token."}]}`,
+			want:       ErrUnauthorized,
+			want_codes: []entities.ErrorCode{89},
+		},
+		{
+			name:       "rate limited",
+			status:     http.StatusTooManyRequests,
+			body:       `{"title":"Too Many Requests"}`,
+			want:       ErrRateLimited,
+			want_title: "Too Many Requests",
+		},
+		{
+			name:   "body which is not json",
+			status: http.StatusBadGateway,
+			body:   "<html>Bad Gateway</html>",
+			want:   ErrServerError,
+		},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Rate-Limit-Limit", "300")
+			w.Header().Set("X-Rate-Limit-Remaining", "299")
+			w.Header().Set("X-Rate-Limit-Reset", "1650000000")
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		}))
+		client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+		_, err := client.GetTweet("1", nil)
+		server.Close()
+
+		var api_error *APIError
+		if !errors.As(err, &api_error) {
+			t.Errorf("%s: error = %v, want an *APIError", test.name, err)
+			continue
+		}
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s: error = %v, want it to match %v", test.name, err, test.want)
+		}
+		if api_error.StatusCode != test.status {
+			t.Errorf("%s: status code = %d, want %d", test.name, api_error.StatusCode, test.status)
+		}
+		if string(api_error.Body) != test.body {
+			t.Errorf("%s: body = %q, want %q", test.name, api_error.Body, test.body)
+		}
+		if api_error.Title != test.want_title {
+			t.Errorf("%s: title = %q, want %q", test.name, api_error.Title, test.want_title)
+		}
+		if codes := api_error.Codes(); len(codes) != len(test.want_codes) || (len(codes) != 0 && codes[0] != test.want_codes[0]) {
+			t.Errorf("%s: codes = %v, want %v", test.name, codes, test.want_codes)
+		}
+		if api_error.RateLimits.Limit != 300 || api_error.RateLimits.Remaining != 299 || api_error.RateLimits.ResetTimestamp != 1650000000 {
+			t.Errorf("%s: rate limits = %+v", test.name, api_error.RateLimits)
+		}
+
+		var non_2xx_error *entities.Non2XXError
+		if !errors.As(err, &non_2xx_error) || non_2xx_error.StatusCode != test.status {
+			t.Errorf("%s: error doesn't unwrap to *entities.Non2XXError", test.name)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/arshamalh/twigo
 
-go 1.16
+go 1.18
 
 require github.com/mrjones/oauth v0.0.0-20190623134757-126b35219450
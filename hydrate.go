@@ -0,0 +1,120 @@
+package twigo
+
+import "github.com/arshamalh/twigo/entities"
+
+// HydratedTweet is a Tweet joined with its expansions from the includes of the response,
+// fields are nil or empty if they are not requested with the "expansions" param.
+type HydratedTweet struct {
+	entities.Tweet
+	// How this Tweet is referenced by its parent, like "quoted", "retweeted" or "replied_to",
+	// empty for top level Tweets.
+	ReferenceType string
+	Author        *entities.User
+	InReplyToUser *entities.User
+	Media         []Media
+	Poll          *Poll
+	Place         *Place
+	Referenced    []HydratedTweet
+}
+
+// Indexed includes, so each lookup is a map access instead of a scan.
+type includes_index struct {
+	users  map[string]*entities.User
+	tweets map[string]*entities.Tweet
+	media  map[string]*Media
+	polls  map[string]*Poll
+	places map[string]*Place
+}
+
+func (i *IncludesEntity) index() *includes_index {
+	index := &includes_index{
+		users:  make(map[string]*entities.User, len(i.Users)),
+		tweets: make(map[string]*entities.Tweet, len(i.Tweets)),
+		media:  make(map[string]*Media, len(i.Media)),
+		polls:  make(map[string]*Poll, len(i.Polls)),
+		places: make(map[string]*Place, len(i.Places)),
+	}
+	for n := range i.Users {
+		index.users[i.Users[n].ID] = &i.Users[n]
+	}
+	for n := range i.Tweets {
+		index.tweets[i.Tweets[n].ID] = &i.Tweets[n]
+	}
+	for n := range i.Media {
+		index.media[i.Media[n].MediaKey] = &i.Media[n]
+	}
+	for n := range i.Polls {
+		index.polls[i.Polls[n].ID] = &i.Polls[n]
+	}
+	for n := range i.Places {
+		index.places[i.Places[n].ID] = &i.Places[n]
+	}
+	return index
+}
+
+// Joins the tweet with its expansions, visited prevents following the same referenced Tweet twice.
+func (index *includes_index) hydrate(tweet entities.Tweet, visited map[string]bool) HydratedTweet {
+	hydrated := HydratedTweet{
+		Tweet:         tweet,
+		Author:        index.users[tweet.AuthorID],
+		InReplyToUser: index.users[tweet.InReplyToUserID],
+		Place:         index.places[tweet.Geo.PlaceID],
+	}
+
+	for _, media_key := range tweet.Attachments["media_keys"] {
+		if media, ok := index.media[media_key]; ok {
+			hydrated.Media = append(hydrated.Media, *media)
+		}
+	}
+
+	for _, poll_id := range tweet.Attachments["poll_ids"] {
+		if poll, ok := index.polls[poll_id]; ok {
+			hydrated.Poll = poll
+			break
+		}
+	}
+
+	visited[tweet.ID] = true
+	for _, reference := range tweet.ReferencedTweets {
+		referenced, ok := index.tweets[reference.ID]
+		if !ok || visited[reference.ID] {
+			continue
+		}
+		referenced_tweet := index.hydrate(*referenced, visited)
+		referenced_tweet.ReferenceType = reference.Type
+		hydrated.Referenced = append(hydrated.Referenced, referenced_tweet)
+	}
+	delete(visited, tweet.ID)
+
+	return hydrated
+}
+
+// Joins the given Tweets with these includes.
+func (i *IncludesEntity) Hydrate(tweets ...entities.Tweet) []HydratedTweet {
+	index := i.index()
+	hydrated := make([]HydratedTweet, 0, len(tweets))
+	for _, tweet := range tweets {
+		hydrated = append(hydrated, index.hydrate(tweet, map[string]bool{}))
+	}
+	return hydrated
+}
+
+// Returns the Tweet joined with its author, media, poll, place and referenced Tweets.
+func (r *TweetResponse) Hydrate() HydratedTweet {
+	return r.Includes.Hydrate(r.Data)[0]
+}
+
+// Returns the Tweets joined with their authors, media, polls, places and referenced Tweets.
+func (r *TweetsResponse) Hydrate() []HydratedTweet {
+	return r.Includes.Hydrate(r.Data...)
+}
+
+// Returns the Tweets joined with their authors, media, polls, places and referenced Tweets.
+func (r *BookmarkedTweetsResponse) Hydrate() []HydratedTweet {
+	return r.Includes.Hydrate(r.Data...)
+}
+
+// Returns the Tweet joined with its author, media, poll, place and referenced Tweets.
+func (t *StreamTweet) Hydrate() HydratedTweet {
+	return t.Includes.Hydrate(t.Data)[0]
+}
@@ -0,0 +1,86 @@
+package twigo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHydrate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {
+				"id": "1", "text": "quoting", "author_id": "10", "in_reply_to_user_id": "11",
+				"attachments": {"media_keys": ["3_1", "3_missing"], "poll_ids": ["20"]},
+				"geo": {"place_id": "30"},
+				"referenced_tweets": [{"type": "quoted", "id": "2"}, {"type": "replied_to", "id": "missing"}]
+			},
+			"includes": {
+				"users": [{"id": "10", "username": "author"}, {"id": "11", "username": "replied"}, {"id": "12", "username": "quoted"}],
+				"tweets": [{"id": "2", "text": "quoted", "author_id": "12", "referenced_tweets": [{"type": "quoted", "id": "1"}]}],
+				"media": [{"media_key": "3_1", "type": "photo"}],
+				"polls": [{"id": "20", "options": [{"position": 1, "label": "yes"}]}],
+				"places": [{"id": "30", "full_name": "Tehran, Iran"}]
+			}
+		}`))
+	}))
+	defer server.Close()
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+
+	response, err := client.GetTweet("1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweet := response.Hydrate()
+
+	if tweet.Author == nil || tweet.Author.UserName != "author" {
+		t.Errorf("Author = %v, want user 10", tweet.Author)
+	}
+	if tweet.InReplyToUser == nil || tweet.InReplyToUser.UserName != "replied" {
+		t.Errorf("InReplyToUser = %v, want user 11", tweet.InReplyToUser)
+	}
+	if len(tweet.Media) != 1 || tweet.Media[0].Type != "photo" {
+		t.Errorf("Media = %v, want only the included photo", tweet.Media)
+	}
+	if tweet.Poll == nil || len(tweet.Poll.Options) != 1 {
+		t.Errorf("Poll = %v, want poll 20", tweet.Poll)
+	}
+	if tweet.Place == nil || tweet.Place.FullName != "Tehran, Iran" {
+		t.Errorf("Place = %v, want place 30", tweet.Place)
+	}
+
+	// The quoted Tweet is hydrated too, but its reference back to Tweet 1 isn't followed.
+	if len(tweet.Referenced) != 1 {
+		t.Fatalf("Referenced = %v, want only the included Tweet", tweet.Referenced)
+	}
+	quoted := tweet.Referenced[0]
+	if quoted.ID != "2" || quoted.ReferenceType != "quoted" {
+		t.Errorf("referenced Tweet = %s %q, want Tweet 2 quoted", quoted.ID, quoted.ReferenceType)
+	}
+	if quoted.Author == nil || quoted.Author.UserName != "quoted" {
+		t.Errorf("Author of the quoted Tweet = %v, want user 12", quoted.Author)
+	}
+	if len(quoted.Referenced) != 0 {
+		t.Errorf("Referenced of the quoted Tweet = %v, want none", quoted.Referenced)
+	}
+}
+
+func TestHydrateWithoutIncludes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"id":"1","text":"a","author_id":"10"},{"id":"2","text":"b"}],"meta":{"result_count":2}}`))
+	}))
+	defer server.Close()
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+
+	response, err := client.GetUserTweets("42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweets := response.Hydrate()
+	if len(tweets) != 2 || tweets[0].ID != "1" || tweets[1].ID != "2" {
+		t.Fatalf("hydrated Tweets = %v, want Tweets 1 and 2", tweets)
+	}
+	if tweets[0].Author != nil || tweets[0].Poll != nil || tweets[0].Place != nil || tweets[0].Media != nil {
+		t.Errorf("hydrated Tweet = %+v, want no expansions", tweets[0])
+	}
+}
@@ -0,0 +1,209 @@
+package twigo
+
+import (
+	"context"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// Fetches a page of R, an empty token means the first page.
+type page_fetcher[R any] func(ctx context.Context, token string) (*R, error)
+
+// Returns a page_fetcher calling method with a copy of params,
+// the token is passed as token_param, which is "pagination_token" or "next_token" depending on the endpoint.
+func fetcher[R any](params Map, token_param string, method func(ctx context.Context, params Map) (*R, error)) page_fetcher[R] {
+	return func(ctx context.Context, token string) (*R, error) {
+		page_params := make(Map, len(params)+1)
+		for key, value := range params {
+			page_params[key] = value
+		}
+		delete(page_params, "pagination_token")
+		delete(page_params, "next_token")
+		if token != "" {
+			page_params[token_param] = token
+		}
+		return method(ctx, page_params)
+	}
+}
+
+// Paginated responses, like *TweetsResponse or *UsersResponse.
+type paginated[R any, T any] interface {
+	*R
+	items() []T
+	next_token() string
+	NextPageCtx(ctx context.Context) (*R, error)
+}
+
+// Iterator walks over the pages of a paginated endpoint,
+// either page by page with NextPage and Page, or item by item with Next and Item,
+// don't mix the two modes on the same iterator.
+//
+//	it := client.GetUserTweetsIterator(user_id, nil).MaxItems(500)
+//	for it.Next(ctx) {
+//		fmt.Println(it.Item().Text)
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type Iterator[R any, T any] struct {
+	first      func(ctx context.Context) (*R, error)
+	next       func(ctx context.Context, page *R) (*R, error)
+	items      func(page *R) []T
+	next_token func(page *R) string
+	page       *R
+	buffer     []T
+	item       T
+	max_items  int
+	seen       int
+	done       bool
+	err        error
+}
+
+func new_iterator[R any, T any, P paginated[R, T]](first func(ctx context.Context) (*R, error)) *Iterator[R, T] {
+	return &Iterator[R, T]{
+		first:      first,
+		next:       func(ctx context.Context, page *R) (*R, error) { return P(page).NextPageCtx(ctx) },
+		items:      func(page *R) []T { return P(page).items() },
+		next_token: func(page *R) string { return P(page).next_token() },
+	}
+}
+
+// Stops the iterator after n items, zero means no limit.
+//
+// In page mode the page that reaches the limit is the last one, so it may contain more items.
+func (it *Iterator[R, T]) MaxItems(n int) *Iterator[R, T] {
+	it.max_items = n
+	return it
+}
+
+// Fetches the next page, returns false when there are no more pages or an error happened.
+func (it *Iterator[R, T]) NextPage(ctx context.Context) bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.max_items > 0 && it.seen >= it.max_items {
+		it.done = true
+		return false
+	}
+
+	var page *R
+	if it.page == nil {
+		page, it.err = it.first(ctx)
+	} else {
+		page, it.err = it.next(ctx, it.page)
+	}
+	if it.err != nil {
+		return false
+	}
+
+	it.page = page
+	it.seen += len(it.items(page))
+	if it.next_token(page) == "" {
+		it.done = true
+	}
+	return true
+}
+
+// Returns the current page.
+func (it *Iterator[R, T]) Page() *R {
+	return it.page
+}
+
+// Moves to the next item, fetching new pages when needed,
+// returns false when there are no more items or an error happened.
+func (it *Iterator[R, T]) Next(ctx context.Context) bool {
+	if it.max_items > 0 && it.seen >= it.max_items {
+		return false
+	}
+
+	for len(it.buffer) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.page == nil {
+			it.page, it.err = it.first(ctx)
+		} else {
+			it.page, it.err = it.next(ctx, it.page)
+		}
+		if it.err != nil {
+			return false
+		}
+		it.buffer = it.items(it.page)
+		if it.next_token(it.page) == "" {
+			it.done = true
+		}
+	}
+
+	it.item, it.buffer = it.buffer[0], it.buffer[1:]
+	it.seen++
+	if it.max_items > 0 && it.seen >= it.max_items {
+		it.buffer = nil
+	}
+	return true
+}
+
+// Returns the current item.
+func (it *Iterator[R, T]) Item() T {
+	return it.item
+}
+
+// Returns the error that stopped the iterator, if any.
+func (it *Iterator[R, T]) Err() error {
+	return it.err
+}
+
+func (r *TweetsResponse) items() []entities.Tweet {
+	return r.Data
+}
+
+func (r *TweetsResponse) next_token() string {
+	return r.Meta.NextToken
+}
+
+func (r *BookmarkedTweetsResponse) items() []entities.Tweet {
+	return r.Data
+}
+
+func (r *BookmarkedTweetsResponse) next_token() string {
+	return r.Meta.NextToken
+}
+
+func (r *UsersResponse) items() []entities.User {
+	return r.Data
+}
+
+func (r *UsersResponse) next_token() string {
+	return r.Meta.NextToken
+}
+
+func (r *MutedUsersResponse) items() []entities.User {
+	return r.Data
+}
+
+func (r *MutedUsersResponse) next_token() string {
+	return r.Meta.NextToken
+}
+
+func (r *ListsResponse) items() []List {
+	return r.Data
+}
+
+func (r *ListsResponse) next_token() string {
+	return r.Meta.NextToken
+}
+
+func (r *TweetsCountResponse) items() []CountBucket {
+	return r.Data
+}
+
+func (r *TweetsCountResponse) next_token() string {
+	return r.Meta.NextToken
+}
+
+func (r *DMEventsResponse) items() []entities.DMEvent {
+	return r.Data
+}
+
+func (r *DMEventsResponse) next_token() string {
+	return r.Meta.NextToken
+}
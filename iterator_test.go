@@ -0,0 +1,129 @@
+package twigo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A paginated endpoint of Tweets, with page_size Tweets per page,
+// it fails with a 503 when the page at fail_at is requested, if it's set.
+type fake_pages struct {
+	mu        sync.Mutex
+	ids       []string
+	page_size int
+	fail_at   int
+	// Token params of the requests, empty for the first page.
+	tokens []string
+}
+
+func (f *fake_pages) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	token := r.URL.Query().Get("pagination_token") + r.URL.Query().Get("next_token")
+	f.tokens = append(f.tokens, token)
+	start, _ := strconv.Atoi(token)
+	if f.fail_at != 0 && start == f.fail_at {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	end := start + f.page_size
+	if end > len(f.ids) {
+		end = len(f.ids)
+	}
+	data := []string{}
+	for _, id := range f.ids[start:end] {
+		data = append(data, fmt.Sprintf(`{"id":%q,"text":"Tweet %s"}`, id, id))
+	}
+	next_token := ""
+	if end < len(f.ids) {
+		next_token = fmt.Sprintf(`,"next_token":"%d"`, end)
+	}
+	fmt.Fprintf(w, `{"data":[%s],"meta":{"result_count":%d%s}}`, strings.Join(data, ","), end-start, next_token)
+}
+
+func TestIterator(t *testing.T) {
+	ids := []string{"1", "2", "3", "4", "5"}
+	tests := []struct {
+		name      string
+		max_items int
+		fail_at   int
+		// Expected items, requested tokens, and whether or not the iterator stops with an error.
+		want        string
+		want_tokens string
+		want_err    bool
+	}{
+		{"all items", 0, 0, "1 2 3 4 5", ",2,4", false},
+		{"max items", 3, 0, "1 2 3", ",2", false},
+		{"max items at the end of a page", 4, 0, "1 2 3 4", ",2", false},
+		{"error of a page", 0, 2, "1 2", ",2", true},
+	}
+	for _, test := range tests {
+		pages := &fake_pages{ids: ids, page_size: 2, fail_at: test.fail_at}
+		server := httptest.NewServer(pages)
+		client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+
+		it := client.GetUserTweetsIterator("42", nil).MaxItems(test.max_items)
+		got := []string{}
+		for it.Next(context.Background()) {
+			got = append(got, it.Item().ID)
+		}
+		server.Close()
+
+		if strings.Join(got, " ") != test.want {
+			t.Errorf("%s: items = %v, want %s", test.name, got, test.want)
+		}
+		if tokens := strings.Join(pages.tokens, ","); tokens != test.want_tokens {
+			t.Errorf("%s: tokens = %q, want %q", test.name, tokens, test.want_tokens)
+		}
+		if (it.Err() != nil) != test.want_err {
+			t.Errorf("%s: error = %v, want an error: %t", test.name, it.Err(), test.want_err)
+		}
+	}
+}
+
+func TestIteratorPages(t *testing.T) {
+	pages := &fake_pages{ids: []string{"1", "2", "3", "4", "5"}, page_size: 2}
+	server := httptest.NewServer(pages)
+	defer server.Close()
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+
+	// Recent search pages with next_token instead of pagination_token.
+	it := client.SearchRecentTweetsIterator("golang", nil)
+	sizes := []int{}
+	for it.NextPage(context.Background()) {
+		sizes = append(sizes, len(it.Page().Data))
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Errorf("page sizes = %v, want [2 2 1]", sizes)
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	pages := &fake_pages{ids: []string{"1", "2", "3"}, page_size: 2}
+	server := httptest.NewServer(pages)
+	defer server.Close()
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+
+	page, err := client.GetUserTweets("42", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err = page.NextPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 1 || page.Data[0].ID != "3" || page.Meta.NextToken != "" {
+		t.Errorf("second page = %+v, want Tweet 3 without a next token", page)
+	}
+}
@@ -0,0 +1,530 @@
+package twigo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	default_upload_url = "https://upload.twitter.com/"
+	v1_prefix          = "1.1/"
+
+	// Size of the chunks of an upload, when UploadOptions doesn't set it.
+	default_chunk_size = 1 << 20
+	// Twitter rejects bigger chunks.
+	max_chunk_size = 5 << 20
+	// Attempts of each chunk, when UploadOptions doesn't set it.
+	default_chunk_attempts = 3
+)
+
+// Returned by UploadMedia when Twitter can't process the uploaded media, like a video with an unsupported codec.
+var ErrMediaProcessing = errors.New("twigo: media processing failed")
+
+// Categories of media, they tell Twitter how the media is going to be used.
+const (
+	MediaCategoryTweetImage = "tweet_image"
+	MediaCategoryTweetGIF   = "tweet_gif"
+	MediaCategoryTweetVideo = "tweet_video"
+	MediaCategoryDMImage    = "dm_image"
+	MediaCategoryDMGIF      = "dm_gif"
+	MediaCategoryDMVideo    = "dm_video"
+	MediaCategorySubtitles  = "subtitles"
+)
+
+// Points the media uploads of the client to another host, upload.twitter.com by default,
+// "1.1/media/upload.json" and the other media paths are added by twigo itself.
+//
+//	twigo.WithUploadURL("http://localhost:8080")
+func WithUploadURL(upload_url string) ClientOption {
+	return func(c *Client) {
+		c.uploadURL = strings.TrimSuffix(upload_url, "/") + "/"
+	}
+}
+
+func (c *Client) upload_url(path string) string {
+	if c.uploadURL == "" {
+		return default_upload_url + v1_prefix + path
+	}
+	return c.uploadURL + v1_prefix + path
+}
+
+// Options of UploadMedia, they can all be left empty.
+type UploadOptions struct {
+	// MIME type of the media, like "image/png" or "video/mp4", detected from its first bytes if it's empty.
+	MediaType string
+	// One of the MediaCategory constants, guessed from the media type for Tweets if it's empty,
+	// use the DM ones for media of Direct Messages.
+	MediaCategory string
+	// Size of the media in bytes, needed before sending it, so if it's zero,
+	// it's taken from files, bytes.Reader and the like, otherwise the whole media is read first.
+	TotalBytes int64
+	// Description of an image or a GIF for people who can't see it, up to 1000 characters.
+	AltText string
+	// Subtitles of a video, each one uploaded before with the MediaCategorySubtitles category.
+	Subtitles []MediaSubtitle
+	// IDs of other users who can use the media in their Tweets, up to 100.
+	AdditionalOwners []string
+	// Size of each chunk, up to 5 MB, 1 MB by default.
+	ChunkSize int
+	// How many times each chunk is sent before giving up, 3 by default,
+	// a failed chunk is sent again alone, not the whole media.
+	ChunkAttempts int
+}
+
+// Subtitles of a video, MediaID is the ID of the uploaded SRT file.
+type MediaSubtitle struct {
+	MediaID string `json:"media_id"`
+	// BCP47 code of the language, like "en".
+	LanguageCode string `json:"language_code"`
+	DisplayName  string `json:"display_name"`
+}
+
+// An uploaded media, pass its MediaID to CreateTweet or SendDMToParticipant.
+type MediaUploadResponse struct {
+	MediaID          string `json:"media_id_string"`
+	MediaKey         string `json:"media_key"`
+	Size             int64  `json:"size"`
+	ExpiresAfterSecs int    `json:"expires_after_secs"`
+	Image            *struct {
+		ImageType string `json:"image_type"`
+		Width     int    `json:"w"`
+		Height    int    `json:"h"`
+	} `json:"image"`
+	Video *struct {
+		VideoType string `json:"video_type"`
+	} `json:"video"`
+	// Only videos and GIFs are processed after being uploaded, it's nil for the other ones.
+	ProcessingInfo *MediaProcessingInfo `json:"processing_info"`
+	RateLimits     RateLimits           `json:"-"`
+}
+
+func (r *MediaUploadResponse) Parse(raw_response *http.Response) (*MediaUploadResponse, error) {
+	err := json.NewDecoder(raw_response.Body).Decode(&r)
+	defer raw_response.Body.Close()
+	r.RateLimits.Set(raw_response.Header)
+	return r, err
+}
+
+type MediaProcessingInfo struct {
+	// One of "pending", "in_progress", "failed" and "succeeded".
+	State           string `json:"state"`
+	CheckAfterSecs  int    `json:"check_after_secs"`
+	ProgressPercent int    `json:"progress_percent"`
+	Error           *struct {
+		Code    int    `json:"code"`
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Uploads an image, a GIF, a video or subtitles from media, and returns its ID once it's ready to be used,
+// it waits for the processing of videos and GIFs to finish, and sets the alt text and subtitles of options.
+//
+// The media is sent in chunks, using the INIT, APPEND, FINALIZE and STATUS commands,
+// it needs OAuth 1.0a user context credentials.
+//
+//	file, _ := os.Open("video.mp4")
+//	media, err := client.UploadMedia(file, nil)
+//	client.CreateTweet("Look!", &twigo.CreateTweetOptions{
+//		Media: &twigo.CreateTweetMedia{MediaIDs: []string{media.MediaID}},
+//	})
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/media/upload-media/uploading-media/chunked-media-upload
+func (c *Client) UploadMedia(media io.Reader, options *UploadOptions) (*MediaUploadResponse, error) {
+	return c.UploadMediaCtx(context.Background(), media, options)
+}
+
+// Same as UploadMedia, but the requests are bound to ctx, including the waits for the processing.
+func (c *Client) UploadMediaCtx(ctx context.Context, media io.Reader, options *UploadOptions) (*MediaUploadResponse, error) {
+	opts := UploadOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+	if !c.has_credentials(OAuth_1a) {
+		return nil, fmt.Errorf("%w: media upload needs %s", ErrNoCredentials, OAuth_1a)
+	}
+
+	// The size is taken before peeking, which reads ahead from media.
+	if opts.TotalBytes == 0 {
+		opts.TotalBytes = media_size(media)
+	}
+	reader := bufio.NewReaderSize(media, 512)
+	if opts.MediaType == "" {
+		head, err := reader.Peek(512)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		opts.MediaType = detect_media_type(head)
+	}
+	if opts.MediaCategory == "" {
+		opts.MediaCategory = media_category(opts.MediaType)
+		if opts.MediaCategory == "" {
+			return nil, invalid_params("can't upload media of type %s", opts.MediaType)
+		}
+	}
+
+	var content io.Reader = reader
+	if opts.TotalBytes == 0 {
+		// The size must be known before sending anything.
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		opts.TotalBytes = int64(len(data))
+		content = bytes.NewReader(data)
+	}
+	if limit := media_size_limit(opts.MediaCategory); limit != 0 && opts.TotalBytes > limit {
+		return nil, invalid_params("%s media can't be bigger than %d bytes", opts.MediaCategory, limit)
+	}
+
+	uploaded, err := c.media_init(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.media_append(ctx, uploaded.MediaID, content, &opts); err != nil {
+		return nil, err
+	}
+	if uploaded, err = c.media_finalize(ctx, uploaded.MediaID); err != nil {
+		return nil, err
+	}
+	if uploaded, err = c.wait_for_processing(ctx, uploaded); err != nil {
+		return nil, err
+	}
+
+	if opts.AltText != "" {
+		if err := c.SetMediaAltTextCtx(ctx, uploaded.MediaID, opts.AltText); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Subtitles) != 0 {
+		if err := c.SetMediaSubtitlesCtx(ctx, uploaded.MediaID, opts.MediaCategory, opts.Subtitles); err != nil {
+			return nil, err
+		}
+	}
+	return uploaded, nil
+}
+
+// Checks the options and fills the defaults.
+func (o *UploadOptions) complete() error {
+	if o.ChunkSize == 0 {
+		o.ChunkSize = default_chunk_size
+	}
+	if o.ChunkSize < 0 || o.ChunkSize > max_chunk_size {
+		return invalid_params("chunk size must be between 1 and %d bytes", max_chunk_size)
+	}
+	if o.ChunkAttempts <= 0 {
+		o.ChunkAttempts = default_chunk_attempts
+	}
+	if length := len([]rune(o.AltText)); length > 1000 {
+		return invalid_params("alt text can't be longer than 1000 characters")
+	}
+	if len(o.AdditionalOwners) > 100 {
+		return invalid_params("media can't have more than 100 additional owners")
+	}
+	if o.MediaCategory == MediaCategorySubtitles && o.MediaType == "" {
+		o.MediaType = "application/x-subrip"
+	}
+	return nil
+}
+
+// Returns the MIME type of media from its first bytes.
+func detect_media_type(head []byte) string {
+	// http.DetectContentType doesn't know QuickTime videos.
+	if len(head) >= 12 && string(head[4:12]) == "ftypqt  " {
+		return "video/quicktime"
+	}
+	media_type, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return media_type
+}
+
+// Returns the category of Tweet media of the MIME type, empty if it can't be uploaded.
+func media_category(media_type string) string {
+	switch {
+	case media_type == "image/gif":
+		return MediaCategoryTweetGIF
+	case media_type == "image/jpeg", media_type == "image/png", media_type == "image/webp", media_type == "image/bmp":
+		return MediaCategoryTweetImage
+	case media_type == "video/mp4", media_type == "video/quicktime":
+		return MediaCategoryTweetVideo
+	}
+	return ""
+}
+
+// Returns the biggest size of media of the category, zero if it's not known.
+func media_size_limit(category string) int64 {
+	switch category {
+	case MediaCategoryTweetImage, MediaCategoryDMImage:
+		return 5 << 20
+	case MediaCategoryTweetGIF, MediaCategoryDMGIF:
+		return 15 << 20
+	case MediaCategoryTweetVideo, MediaCategoryDMVideo:
+		return 512 << 20
+	}
+	return 0
+}
+
+// Returns the number of bytes left in media, if it can tell it without reading it, zero otherwise.
+func media_size(media io.Reader) int64 {
+	switch m := media.(type) {
+	case interface{ Len() int }:
+		return int64(m.Len())
+	case *os.File:
+		info, err := m.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0
+		}
+		offset, err := m.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0
+		}
+		return info.Size() - offset
+	}
+	return 0
+}
+
+// Sends a command of the media upload endpoint, with a form body, or in the query string for GET.
+func (c *Client) media_command(ctx context.Context, method string, form url.Values) (*MediaUploadResponse, error) {
+	full_route := c.upload_url("media/upload.json")
+	var body io.Reader
+	if method == "GET" {
+		full_route += "?" + form.Encode()
+	} else {
+		body = strings.NewReader(form.Encode())
+	}
+
+	request, err := c.new_request(ContextWithOAuth(ctx, OAuth_1a), method, full_route, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	response, err := c.do(c.authorizedClient, request)
+	if err != nil {
+		return nil, err
+	}
+	return (&MediaUploadResponse{}).Parse(response)
+}
+
+// Starts an upload, Twitter returns the ID of the media.
+func (c *Client) media_init(ctx context.Context, opts *UploadOptions) (*MediaUploadResponse, error) {
+	form := url.Values{
+		"command":        {"INIT"},
+		"total_bytes":    {strconv.FormatInt(opts.TotalBytes, 10)},
+		"media_type":     {opts.MediaType},
+		"media_category": {opts.MediaCategory},
+	}
+	if len(opts.AdditionalOwners) != 0 {
+		form.Set("additional_owners", strings.Join(opts.AdditionalOwners, ","))
+	}
+	return c.media_command(ctx, "POST", form)
+}
+
+// Sends the media in chunks, retrying each failed chunk on its own.
+func (c *Client) media_append(ctx context.Context, media_id string, content io.Reader, opts *UploadOptions) error {
+	// One byte more than INIT declared is enough to know the media is too long, without reading the rest of it.
+	content = io.LimitReader(content, opts.TotalBytes+1)
+	chunk := make([]byte, opts.ChunkSize)
+	sent := int64(0)
+	for segment := 0; ; segment++ {
+		n, err := io.ReadFull(content, chunk)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		if sent+int64(n) > opts.TotalBytes {
+			return invalid_params("media has more than %d bytes", opts.TotalBytes)
+		}
+
+		if err := c.media_append_chunk(ctx, media_id, segment, chunk[:n], opts.ChunkAttempts); err != nil {
+			return fmt.Errorf("twigo: chunk %d of media %s: %w", segment, media_id, err)
+		}
+		sent += int64(n)
+		if n < len(chunk) {
+			break
+		}
+	}
+
+	if sent != opts.TotalBytes {
+		return invalid_params("media has %d bytes, not %d", sent, opts.TotalBytes)
+	}
+	return nil
+}
+
+func (c *Client) media_append_chunk(ctx context.Context, media_id string, segment int, chunk []byte, attempts int) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("command", "APPEND")
+	writer.WriteField("media_id", media_id)
+	writer.WriteField("segment_index", strconv.Itoa(segment))
+	part, err := writer.CreateFormFile("media", "blob")
+	if err != nil {
+		return err
+	}
+	part.Write(chunk)
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	// Sending a segment again replaces it, so the chunk is safe to retry.
+	policy := RetryPolicy{MaxAttempts: attempts, BaseBackoff: time.Second, MaxBackoff: 30 * time.Second, RetryNonIdempotent: true}
+	for attempt := 1; ; attempt++ {
+		request, err := c.new_request(ContextWithOAuth(ctx, OAuth_1a), "POST", c.upload_url("media/upload.json"), bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		// The chunk has its own retries, so the retry policy of the client is not used.
+		response, err := c.send(c.authorizedClient, request)
+		if err == nil {
+			response.Body.Close()
+			return nil
+		}
+
+		wait, ok := policy.wait(err, attempt, request.Method)
+		if !ok || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Ends an upload, videos and GIFs are processed after it.
+func (c *Client) media_finalize(ctx context.Context, media_id string) (*MediaUploadResponse, error) {
+	return c.media_command(ctx, "POST", url.Values{
+		"command":  {"FINALIZE"},
+		"media_id": {media_id},
+	})
+}
+
+// Polls the status of the media until its processing is finished, as often as Twitter asks for.
+func (c *Client) wait_for_processing(ctx context.Context, uploaded *MediaUploadResponse) (*MediaUploadResponse, error) {
+	for uploaded.ProcessingInfo != nil {
+		info := uploaded.ProcessingInfo
+		switch info.State {
+		case "succeeded":
+			return uploaded, nil
+		case "failed":
+			if info.Error != nil {
+				return nil, fmt.Errorf("%w: %s: %s", ErrMediaProcessing, info.Error.Name, info.Error.Message)
+			}
+			return nil, ErrMediaProcessing
+		}
+
+		wait := time.Duration(info.CheckAfterSecs) * time.Second
+		if wait <= 0 {
+			wait = time.Second
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		status, err := c.media_command(ctx, "GET", url.Values{
+			"command":  {"STATUS"},
+			"media_id": {uploaded.MediaID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		// STATUS doesn't return everything FINALIZE does.
+		uploaded.ProcessingInfo = status.ProcessingInfo
+		if status.Video != nil {
+			uploaded.Video = status.Video
+		}
+		uploaded.RateLimits = status.RateLimits
+	}
+	return uploaded, nil
+}
+
+// Sends a JSON body to one of the media metadata endpoints.
+func (c *Client) media_metadata(ctx context.Context, path string, body Map) error {
+	if !c.has_credentials(OAuth_1a) {
+		return fmt.Errorf("%w: media metadata needs %s", ErrNoCredentials, OAuth_1a)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	request, err := c.new_request(ContextWithOAuth(ctx, OAuth_1a), "POST", c.upload_url(path), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	response, err := c.do(c.authorizedClient, request)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// Sets the alt text of an uploaded image or GIF, the description of the media for people who can't see it.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/media/upload-media/api-reference/post-media-metadata-create
+func (c *Client) SetMediaAltText(media_id, alt_text string) error {
+	return c.SetMediaAltTextCtx(context.Background(), media_id, alt_text)
+}
+
+// Same as SetMediaAltText, but the request is bound to ctx.
+func (c *Client) SetMediaAltTextCtx(ctx context.Context, media_id, alt_text string) error {
+	if length := len([]rune(alt_text)); length == 0 || length > 1000 {
+		return invalid_params("alt text must be 1 to 1000 characters")
+	}
+	return c.media_metadata(ctx, "media/metadata/create.json", Map{
+		"media_id": media_id,
+		"alt_text": Map{"text": alt_text},
+	})
+}
+
+// Attaches subtitles to an uploaded video, media_category is the category the video was uploaded with,
+// like MediaCategoryTweetVideo.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/media/upload-media/api-reference/post-media-subtitles-create
+func (c *Client) SetMediaSubtitles(media_id, media_category string, subtitles []MediaSubtitle) error {
+	return c.SetMediaSubtitlesCtx(context.Background(), media_id, media_category, subtitles)
+}
+
+// Same as SetMediaSubtitles, but the request is bound to ctx.
+func (c *Client) SetMediaSubtitlesCtx(ctx context.Context, media_id, media_category string, subtitles []MediaSubtitle) error {
+	if len(subtitles) == 0 {
+		return invalid_params("subtitles are required")
+	}
+	for _, subtitle := range subtitles {
+		if subtitle.MediaID == "" || subtitle.LanguageCode == "" {
+			return invalid_params("subtitles need media_id and language_code")
+		}
+	}
+	return c.media_metadata(ctx, "media/subtitles/create.json", Map{
+		"media_id":       media_id,
+		"media_category": media_category,
+		"subtitle_info":  Map{"subtitles": subtitles},
+	})
+}
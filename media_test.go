@@ -0,0 +1,245 @@
+package twigo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// The media upload endpoints, processing videos for two STATUS checks.
+type fake_upload_server struct {
+	mu sync.Mutex
+	// Commands and requests it got, like "INIT", "APPEND 0" or "POST /1.1/media/metadata/create.json".
+	log []string
+	// Form of the INIT command.
+	init url.Values
+	// Received chunks by their segment index.
+	chunks map[string][]byte
+	// Segment which fails once with a 503, -1 for none.
+	fail_segment string
+	// Bodies of the metadata requests.
+	metadata []string
+	// FINALIZE answers with a failed processing.
+	fail_processing bool
+	statuses        int
+	unsigned        int
+}
+
+func new_fake_upload_server(t *testing.T) (*fake_upload_server, *Client) {
+	fake := &fake_upload_server{chunks: map[string][]byte{}, fail_segment: "-1"}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client, _ := NewClient(
+		&Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret"},
+		WithUploadURL(server.URL),
+	)
+	return fake, client
+}
+
+func (f *fake_upload_server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !oauth1_signature_valid(r, "secret", "secret") {
+		f.unsigned++
+	}
+	if r.URL.Path != "/1.1/media/upload.json" {
+		body, _ := io.ReadAll(r.Body)
+		f.log = append(f.log, r.Method+" "+r.URL.Path)
+		f.metadata = append(f.metadata, string(body))
+		return
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		r.ParseMultipartForm(max_chunk_size)
+		segment := r.FormValue("segment_index")
+		f.log = append(f.log, "APPEND "+segment)
+		if segment == f.fail_segment {
+			f.fail_segment = "-1"
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		file, _, _ := r.FormFile("media")
+		f.chunks[segment], _ = io.ReadAll(file)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	command := r.Form.Get("command")
+	f.log = append(f.log, command)
+	switch command {
+	case "INIT":
+		f.init = r.PostForm
+		w.Write([]byte(`{"media_id":710511363345354753,"media_id_string":"710511363345354753","expires_after_secs":86400}`))
+	case "FINALIZE":
+		switch {
+		case f.fail_processing:
+			w.Write([]byte(`{"media_id_string":"710511363345354753","processing_info":{"state":"failed","error":{"code":1,"name":"InvalidMedia","message":"Unsupported video codec"}}}`))
+		case strings.HasPrefix(f.init.Get("media_type"), "video/"):
+			w.Write([]byte(`{"media_id_string":"710511363345354753","size":3000,"processing_info":{"state":"pending","check_after_secs":1}}`))
+		default:
+			w.Write([]byte(`{"media_id_string":"710511363345354753","size":3000,"image":{"image_type":"image/png","w":10,"h":10}}`))
+		}
+	case "STATUS":
+		f.statuses++
+		if f.statuses == 1 {
+			w.Write([]byte(`{"media_id_string":"710511363345354753","processing_info":{"state":"in_progress","check_after_secs":1,"progress_percent":50}}`))
+			return
+		}
+		w.Write([]byte(`{"media_id_string":"710511363345354753","processing_info":{"state":"succeeded","progress_percent":100},"video":{"video_type":"video/mp4"}}`))
+	}
+}
+
+// Returns the received chunks joined in the order of their segments.
+func (f *fake_upload_server) content() []byte {
+	content := []byte{}
+	for segment := 0; ; segment++ {
+		chunk, ok := f.chunks[fmt.Sprint(segment)]
+		if !ok {
+			return content
+		}
+		content = append(content, chunk...)
+	}
+}
+
+func TestUploadMedia(t *testing.T) {
+	fake, client := new_fake_upload_server(t)
+	fake.fail_segment = "1"
+	video := append([]byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), bytes.Repeat([]byte{1}, 3000)...)
+	subtitles := []MediaSubtitle{{MediaID: "9", LanguageCode: "en", DisplayName: "English"}}
+
+	// A reader without a known size, so it's read before INIT.
+	media, err := client.UploadMedia(io.MultiReader(bytes.NewReader(video)), &UploadOptions{ChunkSize: 1024, Subtitles: subtitles})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "INIT, APPEND 0, APPEND 1, APPEND 1, APPEND 2, FINALIZE, STATUS, STATUS, POST /1.1/media/subtitles/create.json"
+	if got := strings.Join(fake.log, ", "); got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+	if fake.init.Get("total_bytes") != fmt.Sprint(len(video)) || fake.init.Get("media_type") != "video/mp4" || fake.init.Get("media_category") != MediaCategoryTweetVideo {
+		t.Errorf("INIT = %v, want the size, type and category of the video", fake.init)
+	}
+	if !bytes.Equal(fake.content(), video) {
+		t.Errorf("uploaded %d bytes, want the %d bytes of the video", len(fake.content()), len(video))
+	}
+	if media.MediaID != "710511363345354753" || media.ProcessingInfo.State != "succeeded" || media.Video == nil || media.Size != 3000 {
+		t.Errorf("media = %+v, want the processed video", media)
+	}
+	if len(fake.metadata) != 1 || !strings.Contains(fake.metadata[0], `"subtitles":[{"media_id":"9","language_code":"en","display_name":"English"}]`) {
+		t.Errorf("subtitles request = %v", fake.metadata)
+	}
+	if fake.unsigned != 0 {
+		t.Errorf("%d requests aren't signed correctly", fake.unsigned)
+	}
+}
+
+func TestUploadImage(t *testing.T) {
+	fake, client := new_fake_upload_server(t)
+	image := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 100)...)
+
+	media, err := client.UploadMedia(bytes.NewReader(image), &UploadOptions{AltText: "A gopher", AdditionalOwners: []string{"2", "3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Images aren't processed, so there is no STATUS.
+	want := "INIT, APPEND 0, FINALIZE, POST /1.1/media/metadata/create.json"
+	if got := strings.Join(fake.log, ", "); got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+	if fake.init.Get("media_category") != MediaCategoryTweetImage || fake.init.Get("additional_owners") != "2,3" {
+		t.Errorf("INIT = %v, want an image with its additional owners", fake.init)
+	}
+	if media.Image == nil || media.Image.Width != 10 {
+		t.Errorf("media = %+v, want the image", media)
+	}
+	metadata := Map{}
+	json.Unmarshal([]byte(fake.metadata[0]), &metadata)
+	if fmt.Sprint(metadata) != "map[alt_text:map[text:A gopher] media_id:710511363345354753]" {
+		t.Errorf("alt text request = %s", fake.metadata[0])
+	}
+}
+
+func TestUploadMediaProcessingFailed(t *testing.T) {
+	fake, client := new_fake_upload_server(t)
+	fake.fail_processing = true
+	video := append([]byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), bytes.Repeat([]byte{1}, 100)...)
+
+	_, err := client.UploadMedia(bytes.NewReader(video), nil)
+	if !errors.Is(err, ErrMediaProcessing) || !strings.Contains(err.Error(), "Unsupported video codec") {
+		t.Errorf("error = %v, want %v with its message", err, ErrMediaProcessing)
+	}
+}
+
+// A reader which never ends, counting its reads.
+type endless_reader struct {
+	reads int
+}
+
+func (r *endless_reader) Read(p []byte) (int, error) {
+	r.reads++
+	return len(p), nil
+}
+
+func TestUploadMediaInvalid(t *testing.T) {
+	fake, client := new_fake_upload_server(t)
+	image := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 2000)...)
+
+	tests := []struct {
+		name    string
+		media   io.Reader
+		options *UploadOptions
+	}{
+		{"unknown type", strings.NewReader("hello"), nil},
+		{"too big image", bytes.NewReader(image), &UploadOptions{TotalBytes: 6 << 20}},
+		{"too big chunks", bytes.NewReader(image), &UploadOptions{ChunkSize: 6 << 20}},
+		{"too long alt text", bytes.NewReader(image), &UploadOptions{AltText: strings.Repeat("a", 1001)}},
+	}
+	for _, test := range tests {
+		if _, err := client.UploadMedia(test.media, test.options); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("%s: error = %v, want %v", test.name, err, ErrInvalidParams)
+		}
+	}
+	if len(fake.log) != 0 {
+		t.Errorf("requests = %v, want none", fake.log)
+	}
+
+	app, _ := NewBearerOnlyClient("token")
+	if _, err := app.UploadMedia(bytes.NewReader(image), nil); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("error of an app-only client = %v, want %v", err, ErrNoCredentials)
+	}
+}
+
+func TestUploadMediaWrongSize(t *testing.T) {
+	fake, client := new_fake_upload_server(t)
+
+	// The upload stops at the first chunk going past TotalBytes, without reading the rest.
+	endless := &endless_reader{}
+	media := io.MultiReader(bytes.NewReader(make([]byte, 2048)), endless)
+	_, err := client.UploadMedia(media, &UploadOptions{MediaType: "image/png", TotalBytes: 2000, ChunkSize: 1024})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error of a too long media = %v, want %v", err, ErrInvalidParams)
+	}
+	if got := strings.Join(fake.log, ", "); got != "INIT, APPEND 0" || endless.reads > 1 {
+		t.Errorf("requests = %s after %d reads past the media, want only its first chunk", got, endless.reads)
+	}
+
+	fake.log = nil
+	_, err = client.UploadMedia(bytes.NewReader(make([]byte, 1500)), &UploadOptions{MediaType: "image/png", TotalBytes: 2000, ChunkSize: 1024})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error of a too short media = %v, want %v", err, ErrInvalidParams)
+	}
+	if got := strings.Join(fake.log, ", "); got != "INIT, APPEND 0, APPEND 1" {
+		t.Errorf("requests = %s, want no FINALIZE", got)
+	}
+}
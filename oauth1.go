@@ -0,0 +1,153 @@
+package twigo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/mrjones/oauth"
+)
+
+// Returned by HandleCallback when the user didn't authorize the app.
+var ErrOAuth1Denied = errors.New("twigo: oauth1 authorization was denied")
+
+// Returned by HandleCallback when the callback is not about the request token of the authorization.
+var ErrOAuth1Token = errors.New("twigo: oauth1 token doesn't match")
+
+// The three-legged OAuth 1.0a flow, to get the access token of a user who authorizes the app,
+// either with a callback URL of the app, or with a PIN the user copies back to it.
+//
+//	flow := twigo.NewOAuth1Flow(consumer_key, consumer_secret)
+//	authorization, err := flow.Authorize("https://example.com/callback")
+//	// Send the user to authorization.URL, keep authorization until the callback.
+//	credentials, err := flow.HandleCallback(authorization, callback_request.URL.Query())
+//	client, err := twigo.NewClient(flow.Config(credentials))
+type OAuth1Flow struct {
+	consumerKey    string
+	consumerSecret string
+	consumer       *oauth.Consumer
+}
+
+// A request token waiting for the user to authorize it,
+// keep it, in the session of the user for example, until the callback or the PIN.
+type OAuth1Authorization struct {
+	// Where the user should be sent to authorize the app.
+	URL    string
+	Token  string
+	Secret string
+}
+
+// The access token of a user who authorized the app.
+type OAuth1Credentials struct {
+	AccessToken  string
+	AccessSecret string
+	ScreenName   string
+	UserID       string
+}
+
+// Returns the three-legged flow of the app,
+// options like WithBaseURL and WithHTTPClient apply to its requests too.
+func NewOAuth1Flow(consumer_key, consumer_secret string, opts ...ClientOption) *OAuth1Flow {
+	client := &Client{baseURL: default_base_url}
+	client.applyOptions(opts)
+	return &OAuth1Flow{
+		consumerKey:    consumer_key,
+		consumerSecret: consumer_secret,
+		consumer:       client.new_consumer(consumer_key, consumer_secret),
+	}
+}
+
+// Gets a request token, callback_url is where the user is sent back after authorizing the app,
+// it must be one of the callback URLs of the app.
+func (f *OAuth1Flow) Authorize(callback_url string) (*OAuth1Authorization, error) {
+	if callback_url == "" {
+		return nil, invalid_params("callback_url is required, use AuthorizePIN for the PIN flow")
+	}
+	return f.request_token(callback_url)
+}
+
+// Gets a request token for the out-of-band flow,
+// the user sees a PIN after authorizing the app, which must be passed to ExchangePIN.
+func (f *OAuth1Flow) AuthorizePIN() (*OAuth1Authorization, error) {
+	return f.request_token("oob")
+}
+
+func (f *OAuth1Flow) request_token(callback_url string) (*OAuth1Authorization, error) {
+	request_token, authorize_url, err := f.consumer.GetRequestTokenAndUrl(callback_url)
+	if err != nil {
+		return nil, fmt.Errorf("twigo: oauth1 request token: %w", err)
+	}
+	return &OAuth1Authorization{
+		URL:    authorize_url,
+		Token:  request_token.Token,
+		Secret: request_token.Secret,
+	}, nil
+}
+
+// Finishes an authorization using the query of the callback request,
+// which carries the request token and its verifier.
+func (f *OAuth1Flow) HandleCallback(authorization *OAuth1Authorization, query url.Values) (*OAuth1Credentials, error) {
+	if query.Get("denied") != "" {
+		return nil, ErrOAuth1Denied
+	}
+	if query.Get("oauth_token") != authorization.Token {
+		return nil, ErrOAuth1Token
+	}
+	return f.exchange(authorization, query.Get("oauth_verifier"))
+}
+
+// Finishes an out-of-band authorization with the PIN the user got.
+func (f *OAuth1Flow) ExchangePIN(authorization *OAuth1Authorization, pin string) (*OAuth1Credentials, error) {
+	return f.exchange(authorization, strings.TrimSpace(pin))
+}
+
+// Runs the whole PIN flow in a terminal, it writes the authorize URL to out,
+// and reads the PIN from a line of in, handy for onboarding bot accounts.
+func (f *OAuth1Flow) AuthorizeWithPIN(in io.Reader, out io.Writer) (*OAuth1Credentials, error) {
+	authorization, err := f.AuthorizePIN()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(out, "Open this URL, authorize the app, and enter the PIN:\n%s\nPIN: ", authorization.URL)
+	pin, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && (err != io.EOF || pin == "") {
+		return nil, err
+	}
+	return f.ExchangePIN(authorization, pin)
+}
+
+// Exchanges the request token and its verifier for the access token.
+func (f *OAuth1Flow) exchange(authorization *OAuth1Authorization, verifier string) (*OAuth1Credentials, error) {
+	if verifier == "" {
+		return nil, invalid_params("oauth verifier is required")
+	}
+
+	access_token, err := f.consumer.AuthorizeToken(&oauth.RequestToken{
+		Token:  authorization.Token,
+		Secret: authorization.Secret,
+	}, verifier)
+	if err != nil {
+		return nil, fmt.Errorf("twigo: oauth1 access token: %w", err)
+	}
+
+	return &OAuth1Credentials{
+		AccessToken:  access_token.Token,
+		AccessSecret: access_token.Secret,
+		ScreenName:   access_token.AdditionalData["screen_name"],
+		UserID:       access_token.AdditionalData["user_id"],
+	}, nil
+}
+
+// Returns the config of a client acting as the user of credentials, pass it to NewClient.
+func (f *OAuth1Flow) Config(credentials *OAuth1Credentials) *Config {
+	return &Config{
+		ConsumerKey:    f.consumerKey,
+		ConsumerSecret: f.consumerSecret,
+		AccessToken:    credentials.AccessToken,
+		AccessSecret:   credentials.AccessSecret,
+	}
+}
@@ -0,0 +1,190 @@
+package twigo
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Parses the OAuth params of the Authorization header of the request.
+func oauth1_header_params(r *http.Request) map[string]string {
+	params := map[string]string{}
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "OAuth ") {
+		return params
+	}
+	for _, pair := range strings.Split(strings.TrimPrefix(header, "OAuth "), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		value, _ = url.PathUnescape(strings.Trim(value, `"`))
+		params[key] = value
+	}
+	return params
+}
+
+func oauth1_escape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(url.QueryEscape(s), "+", "%20"), "%7E", "~")
+}
+
+// Whether or not the request is signed with HMAC-SHA1 by the given secrets, as in RFC 5849.
+func oauth1_signature_valid(r *http.Request, consumer_secret, token_secret string) bool {
+	header := oauth1_header_params(r)
+	if header["oauth_signature_method"] != "HMAC-SHA1" || header["oauth_nonce"] == "" || header["oauth_timestamp"] == "" {
+		return false
+	}
+
+	params := []string{}
+	for key, value := range header {
+		if key != "oauth_signature" && key != "realm" {
+			params = append(params, oauth1_escape(key)+"="+oauth1_escape(value))
+		}
+	}
+	r.ParseForm()
+	for _, values := range []url.Values{r.URL.Query(), r.PostForm} {
+		for key, list := range values {
+			for _, value := range list {
+				params = append(params, oauth1_escape(key)+"="+oauth1_escape(value))
+			}
+		}
+	}
+	sort.Strings(params)
+
+	base := strings.Join([]string{
+		r.Method,
+		oauth1_escape("http://" + r.Host + r.URL.Path),
+		oauth1_escape(strings.Join(params, "&")),
+	}, "&")
+	mac := hmac.New(sha1.New, []byte(oauth1_escape(consumer_secret)+"&"+oauth1_escape(token_secret)))
+	mac.Write([]byte(base))
+	return header["oauth_signature"] == base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Twitter's OAuth 1.0a endpoints for the app with the key "key" and the secret "secret",
+// the request token is "request", the access token is "42-access", and the verifier is "verifier".
+type fake_oauth1_provider struct {
+	mu sync.Mutex
+	// Callbacks of the request token requests.
+	callbacks []string
+	// Requests which aren't signed correctly.
+	unsigned []string
+}
+
+func (f *fake_oauth1_provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	params := oauth1_header_params(r)
+	token_secret := ""
+	switch r.URL.Path {
+	case "/oauth/access_token":
+		token_secret = "request-secret"
+	case "/2/tweets/1":
+		token_secret = "access-secret"
+	}
+	if params["oauth_consumer_key"] != "key" || !oauth1_signature_valid(r, "secret", token_secret) {
+		f.unsigned = append(f.unsigned, r.URL.Path)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/oauth/request_token":
+		f.callbacks = append(f.callbacks, params["oauth_callback"])
+		w.Write([]byte("oauth_token=request&oauth_token_secret=request-secret&oauth_callback_confirmed=true"))
+	case "/oauth/access_token":
+		if params["oauth_token"] != "request" || params["oauth_verifier"] != "verifier" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("oauth_token=42-access&oauth_token_secret=access-secret&user_id=42&screen_name=gopher"))
+	case "/2/tweets/1":
+		if params["oauth_token"] != "42-access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":{"id":"1","text":"hi"}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestOAuth1Flow(t *testing.T) {
+	provider := &fake_oauth1_provider{}
+	server := httptest.NewServer(provider)
+	defer server.Close()
+	flow := NewOAuth1Flow("key", "secret", WithBaseURL(server.URL))
+
+	authorization, err := flow.Authorize("https://example.com/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorization.Token != "request" || authorization.URL != server.URL+"/oauth/authorize?oauth_token=request" {
+		t.Errorf("authorization = %+v, want the request token and its authorize URL", authorization)
+	}
+
+	if _, err := flow.HandleCallback(authorization, url.Values{"denied": {"request"}}); !errors.Is(err, ErrOAuth1Denied) {
+		t.Errorf("error of a denied authorization = %v, want %v", err, ErrOAuth1Denied)
+	}
+	if _, err := flow.HandleCallback(authorization, url.Values{"oauth_token": {"another"}, "oauth_verifier": {"verifier"}}); !errors.Is(err, ErrOAuth1Token) {
+		t.Errorf("error of another request token = %v, want %v", err, ErrOAuth1Token)
+	}
+	credentials, err := flow.HandleCallback(authorization, url.Values{"oauth_token": {"request"}, "oauth_verifier": {"verifier"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *credentials != (OAuth1Credentials{AccessToken: "42-access", AccessSecret: "access-secret", ScreenName: "gopher", UserID: "42"}) {
+		t.Errorf("credentials = %+v", credentials)
+	}
+
+	// The client of the credentials signs its requests with them.
+	client, err := NewClient(flow.Config(credentials), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTweet("1", Map{"tweet.fields": "created_at,author_id"}); err != nil {
+		t.Error(err)
+	}
+
+	if len(provider.unsigned) != 0 {
+		t.Errorf("requests which aren't signed correctly: %v", provider.unsigned)
+	}
+	if len(provider.callbacks) != 1 || provider.callbacks[0] != "https://example.com/callback" {
+		t.Errorf("callbacks = %v, want the callback URL", provider.callbacks)
+	}
+}
+
+func TestOAuth1PIN(t *testing.T) {
+	provider := &fake_oauth1_provider{}
+	server := httptest.NewServer(provider)
+	defer server.Close()
+	flow := NewOAuth1Flow("key", "secret", WithBaseURL(server.URL))
+
+	out := &strings.Builder{}
+	credentials, err := flow.AuthorizeWithPIN(strings.NewReader(" verifier\n"), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.AccessToken != "42-access" {
+		t.Errorf("access token = %s, want 42-access", credentials.AccessToken)
+	}
+	if !strings.Contains(out.String(), server.URL+"/oauth/authorize?oauth_token=request") {
+		t.Errorf("output = %q, want the authorize URL", out.String())
+	}
+	if len(provider.callbacks) != 1 || provider.callbacks[0] != "oob" {
+		t.Errorf("callbacks = %v, want oob", provider.callbacks)
+	}
+
+	if _, err := flow.Authorize(""); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error without a callback URL = %v, want %v", err, ErrInvalidParams)
+	}
+	if _, err := flow.ExchangePIN(&OAuth1Authorization{Token: "request"}, " "); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error without a PIN = %v, want %v", err, ErrInvalidParams)
+	}
+}
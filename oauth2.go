@@ -0,0 +1,408 @@
+package twigo
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arshamalh/twigo/utils"
+)
+
+const (
+	default_oauth2_auth_url  = "https://twitter.com/i/oauth2/authorize"
+	default_oauth2_token_url = "https://api.twitter.com/2/oauth2/token"
+)
+
+// Scopes of the OAuth 2.0 user context, ScopeOfflineAccess is needed for a refresh token.
+//
+// https://developer.twitter.com/en/docs/authentication/oauth-2-0/authorization-code
+const (
+	ScopeTweetRead          = "tweet.read"
+	ScopeTweetWrite         = "tweet.write"
+	ScopeTweetModerateWrite = "tweet.moderate.write"
+	ScopeUsersRead          = "users.read"
+	ScopeFollowsRead        = "follows.read"
+	ScopeFollowsWrite       = "follows.write"
+	ScopeOfflineAccess      = "offline.access"
+	ScopeSpaceRead          = "space.read"
+	ScopeMuteRead           = "mute.read"
+	ScopeMuteWrite          = "mute.write"
+	ScopeLikeRead           = "like.read"
+	ScopeLikeWrite          = "like.write"
+	ScopeListRead           = "list.read"
+	ScopeListWrite          = "list.write"
+	ScopeBlockRead          = "block.read"
+	ScopeBlockWrite         = "block.write"
+	ScopeBookmarkRead       = "bookmark.read"
+	ScopeBookmarkWrite      = "bookmark.write"
+)
+
+// Returned by HandleCallback when the state of the callback is not the one of the authorization,
+// the callback may be forged, so the code must not be used.
+var ErrOAuth2State = errors.New("twigo: oauth2 state doesn't match")
+
+// An error of the OAuth 2.0 authorization or token endpoints, like "invalid_grant" for an expired code.
+type OAuth2Error struct {
+	// Zero for errors reported to the callback.
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuth2Error) Error() string {
+	if e.Description == "" {
+		return "twigo: oauth2: " + e.Code
+	}
+	return "twigo: oauth2: " + e.Code + ": " + e.Description
+}
+
+// An OAuth 2.0 app, for the Authorization Code flow with PKCE.
+//
+//	config := &twigo.OAuth2Config{
+//		ClientID:    "...",
+//		RedirectURL: "https://example.com/callback",
+//		Scopes:      []string{twigo.ScopeTweetRead, twigo.ScopeUsersRead, twigo.ScopeBookmarkRead, twigo.ScopeOfflineAccess},
+//	}
+//	authorization, err := config.Authorize()
+//	// Send the user to authorization.URL, keep authorization until the callback.
+//	token, err := config.HandleCallback(ctx, authorization, callback_request.URL.Query())
+//	client, err := twigo.NewOAuth2Client(config.TokenSource(token, &twigo.FileTokenStore{Path: "token.json"}))
+type OAuth2Config struct {
+	ClientID string
+	// Only confidential clients have a secret, public ones leave it empty.
+	ClientSecret string
+	// Must be one of the callback URLs of the app.
+	RedirectURL string
+	Scopes      []string
+	// Default is https://twitter.com/i/oauth2/authorize
+	AuthURL string
+	// Default is https://api.twitter.com/2/oauth2/token
+	TokenURL string
+	// Used for the token requests, default is http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// An authorization waiting for its callback, keep it, in the session of the user for example,
+// since its State and CodeVerifier are needed to finish it.
+type OAuth2Authorization struct {
+	// Where the user should be sent to authorize the app.
+	URL          string
+	State        string
+	CodeVerifier string
+}
+
+// Tokens of an authorized user.
+type OAuth2Token struct {
+	AccessToken string `json:"access_token"`
+	// Only given with the ScopeOfflineAccess scope.
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type"`
+	Scope        string    `json:"scope"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Whether or not the access token expires within margin, tokens without an expiry never do.
+func (t *OAuth2Token) Expired(margin time.Duration) bool {
+	return !t.Expiry.IsZero() && time.Now().Add(margin).After(t.Expiry)
+}
+
+// Starts an authorization, with a random state and a PKCE code verifier.
+func (c *OAuth2Config) Authorize() (*OAuth2Authorization, error) {
+	state, err := random_token(16)
+	if err != nil {
+		return nil, err
+	}
+	code_verifier, err := random_token(32)
+	if err != nil {
+		return nil, err
+	}
+
+	auth_url := c.AuthURL
+	if auth_url == "" {
+		auth_url = default_oauth2_auth_url
+	}
+	challenge := sha256.Sum256([]byte(code_verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.ClientID},
+		"redirect_uri":          {c.RedirectURL},
+		"scope":                 {strings.Join(c.Scopes, " ")},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	return &OAuth2Authorization{
+		URL:          auth_url + "?" + query.Encode(),
+		State:        state,
+		CodeVerifier: code_verifier,
+	}, nil
+}
+
+// Finishes an authorization using the query of the callback request,
+// checks its state and exchanges its code for tokens.
+func (c *OAuth2Config) HandleCallback(ctx context.Context, authorization *OAuth2Authorization, query url.Values) (*OAuth2Token, error) {
+	if code := query.Get("error"); code != "" {
+		return nil, &OAuth2Error{Code: code, Description: query.Get("error_description")}
+	}
+	if query.Get("state") != authorization.State {
+		return nil, ErrOAuth2State
+	}
+	return c.Exchange(ctx, query.Get("code"), authorization.CodeVerifier)
+}
+
+// Exchanges the code of a callback for tokens, code_verifier is the one of its authorization.
+func (c *OAuth2Config) Exchange(ctx context.Context, code, code_verifier string) (*OAuth2Token, error) {
+	if code == "" {
+		return nil, invalid_params("code is required")
+	}
+	return c.token_request(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.RedirectURL},
+		"code_verifier": {code_verifier},
+	})
+}
+
+// Gets a new access token using the refresh token of token,
+// the refresh token is replaced too, so the old one can't be used again.
+func (c *OAuth2Config) Refresh(ctx context.Context, token *OAuth2Token) (*OAuth2Token, error) {
+	if token.RefreshToken == "" {
+		return nil, errors.New("twigo: oauth2 token has no refresh token, ask for the offline.access scope")
+	}
+	refreshed, err := c.token_request(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+	return refreshed, nil
+}
+
+// Sends a request to the token endpoint, public clients send their ID in the form,
+// confidential ones authenticate with basic auth.
+func (c *OAuth2Config) token_request(ctx context.Context, form url.Values) (*OAuth2Token, error) {
+	token_url := c.TokenURL
+	if token_url == "" {
+		token_url = default_oauth2_token_url
+	}
+	if c.ClientSecret == "" {
+		form.Set("client_id", c.ClientID)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", token_url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	http_client := c.HTTPClient
+	if http_client == nil {
+		http_client = http.DefaultClient
+	}
+	response, err := http_client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		oauth2_error := &OAuth2Error{StatusCode: response.StatusCode}
+		if json.Unmarshal(body, oauth2_error) != nil || oauth2_error.Code == "" {
+			oauth2_error.Code = http.StatusText(response.StatusCode)
+		}
+		return nil, oauth2_error
+	}
+
+	token_response := struct {
+		OAuth2Token
+		ExpiresIn int64 `json:"expires_in"`
+	}{}
+	if err := json.Unmarshal(body, &token_response); err != nil {
+		return nil, err
+	}
+	token := token_response.OAuth2Token
+	if token.AccessToken == "" {
+		return nil, errors.New("twigo: oauth2 token response has no access token")
+	}
+	if token_response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token_response.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// Returns a random URL safe string made of n random bytes.
+func random_token(n int) (string, error) {
+	buffer := make([]byte, n)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// Gives the access token of OAuth 2.0 user context requests.
+type TokenSource interface {
+	Token(ctx context.Context) (*OAuth2Token, error)
+}
+
+// Persists the tokens of a user, so they survive restarts and refreshes.
+type TokenStore interface {
+	// Returns the saved token, nil if there is none.
+	Load(ctx context.Context) (*OAuth2Token, error)
+	Save(ctx context.Context, token *OAuth2Token) error
+}
+
+// A TokenStore keeping the token in a JSON file.
+type FileTokenStore struct {
+	Path string
+}
+
+func (s *FileTokenStore) Load(ctx context.Context) (*OAuth2Token, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token := &OAuth2Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("twigo: invalid token file %s: %w", s.Path, err)
+	}
+	return token, nil
+}
+
+// Saves the token, only the owner of the file can read it.
+func (s *FileTokenStore) Save(ctx context.Context, token *OAuth2Token) error {
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(s.Path, data, 0o600)
+}
+
+// A TokenSource refreshing the token of a user before it expires, and saving every new token to its store.
+//
+// It's safe for concurrent use, a single refresh happens at a time,
+// since a refresh token can only be used once.
+type OAuth2TokenSource struct {
+	// How long before the expiry the token is refreshed, default is 1 minute.
+	RefreshBefore time.Duration
+
+	config *OAuth2Config
+	store  TokenStore
+	mu     sync.Mutex
+	token  *OAuth2Token
+	// Whether or not token is not in store yet, like the one HandleCallback returns.
+	unsaved bool
+}
+
+// Returns a TokenSource starting from token, or from the one of store if token is nil,
+// token and the new ones are saved to store, which can be nil too.
+func (c *OAuth2Config) TokenSource(token *OAuth2Token, store TokenStore) *OAuth2TokenSource {
+	return &OAuth2TokenSource{config: c, store: store, token: token, unsaved: token != nil}
+}
+
+// Returns a valid token, refreshing it if it expires soon.
+func (s *OAuth2TokenSource) Token(ctx context.Context) (*OAuth2Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil && s.store != nil {
+		token, err := s.store.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.token = token
+	}
+	if s.token == nil {
+		return nil, fmt.Errorf("%w: no oauth2 token, authorize the user first", ErrNoCredentials)
+	}
+	// The first token is saved on the first call, so a restart before its refresh doesn't lose it.
+	if s.unsaved && s.store != nil {
+		if err := s.store.Save(ctx, s.token); err != nil {
+			return nil, err
+		}
+	}
+	s.unsaved = false
+
+	refresh_before := s.RefreshBefore
+	if refresh_before <= 0 {
+		refresh_before = time.Minute
+	}
+	if s.token.Expired(refresh_before) && s.token.RefreshToken != "" {
+		token, err := s.config.Refresh(ctx, s.token)
+		if err != nil {
+			return nil, err
+		}
+		s.token = token
+		if s.store != nil {
+			if err := s.store.Save(ctx, token); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	token := *s.token
+	return &token, nil
+}
+
+// Returns a client sending user context requests with the tokens of source,
+// like the one of OAuth2Config.TokenSource.
+//
+// The ID of the user is looked up with GetMe on the first call which needs it, like Like or GetBookmarkedTweets,
+// so the users.read and tweet.read scopes are needed for them.
+func NewOAuth2Client(source TokenSource, opts ...ClientOption) (*Client, error) {
+	if source == nil {
+		return nil, invalid_params("token source is required")
+	}
+	client := &Client{
+		baseURL:     default_base_url,
+		tokenSource: source,
+	}
+	client.applyOptions(opts)
+	return client, nil
+}
+
+// Returns the ID of the authenticated user, looking it up with GetMe if it's not known yet.
+func (c *Client) authenticated_user_id(ctx context.Context) (string, error) {
+	c.userMu.Lock()
+	user_id := c.userID
+	c.userMu.Unlock()
+	if user_id != "" || c.tokenSource == nil {
+		return user_id, nil
+	}
+
+	// The lock is not held during the request, so concurrent first calls may each ask for the same ID.
+	me, err := c.GetMeCtx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	c.userMu.Lock()
+	defer c.userMu.Unlock()
+	c.userID = me.Data.ID
+	return c.userID, nil
+}
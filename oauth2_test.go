@@ -0,0 +1,262 @@
+package twigo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A token endpoint, it gives access-1, access-2, ... and refresh-1, refresh-2, ...
+// rejecting any code other than "code" and any refresh token other than the last one it gave.
+type fake_token_endpoint struct {
+	mu       sync.Mutex
+	issued   int
+	requests []url.Values
+	// Basic auth of the last request, empty for public clients.
+	username, password string
+	// Whether or not refresh responses leave out the refresh token.
+	keep_refresh_token bool
+}
+
+func (f *fake_token_endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r.ParseForm()
+	f.requests = append(f.requests, r.PostForm)
+	f.username, f.password, _ = r.BasicAuth()
+
+	valid := false
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		valid = r.PostForm.Get("code") == "code"
+	case "refresh_token":
+		valid = r.PostForm.Get("refresh_token") == fmt.Sprintf("refresh-%d", f.issued)
+	}
+	if !valid {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Value passed for the token was invalid."}`)
+		return
+	}
+
+	f.issued++
+	refresh_token := fmt.Sprintf(`,"refresh_token":"refresh-%d"`, f.issued)
+	if f.keep_refresh_token && r.PostForm.Get("grant_type") == "refresh_token" {
+		refresh_token = ""
+	}
+	fmt.Fprintf(w, `{"token_type":"bearer","expires_in":7200,"access_token":"access-%d","scope":"tweet.read offline.access"%s}`, f.issued, refresh_token)
+}
+
+func new_test_oauth2_config(t *testing.T) (*OAuth2Config, *fake_token_endpoint) {
+	endpoint := &fake_token_endpoint{}
+	server := httptest.NewServer(endpoint)
+	t.Cleanup(server.Close)
+	return &OAuth2Config{
+		ClientID:    "client",
+		RedirectURL: "https://example.com/callback",
+		Scopes:      []string{ScopeTweetRead, ScopeOfflineAccess},
+		TokenURL:    server.URL,
+	}, endpoint
+}
+
+func TestOAuth2Authorize(t *testing.T) {
+	config, _ := new_test_oauth2_config(t)
+	authorization, err := config.Authorize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth_url, _ := url.Parse(authorization.URL)
+	query := auth_url.Query()
+	if auth_url.Host != "twitter.com" || auth_url.Path != "/i/oauth2/authorize" {
+		t.Errorf("URL = %s, want the default authorization URL", authorization.URL)
+	}
+	if query.Get("client_id") != "client" || query.Get("scope") != "tweet.read offline.access" || query.Get("state") != authorization.State {
+		t.Errorf("query = %v", query)
+	}
+	challenge := sha256.Sum256([]byte(authorization.CodeVerifier))
+	if query.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(challenge[:]) || query.Get("code_challenge_method") != "S256" {
+		t.Errorf("code challenge = %q, want the S256 challenge of the code verifier", query.Get("code_challenge"))
+	}
+
+	another, _ := config.Authorize()
+	if another.State == authorization.State || another.CodeVerifier == authorization.CodeVerifier {
+		t.Error("authorizations share their state or code verifier")
+	}
+}
+
+func TestOAuth2HandleCallback(t *testing.T) {
+	config, endpoint := new_test_oauth2_config(t)
+	authorization := &OAuth2Authorization{State: "state", CodeVerifier: "verifier"}
+
+	_, err := config.HandleCallback(context.Background(), authorization, url.Values{"error": {"access_denied"}})
+	var oauth2_error *OAuth2Error
+	if !errors.As(err, &oauth2_error) || oauth2_error.Code != "access_denied" {
+		t.Errorf("error of a denied authorization = %v, want access_denied", err)
+	}
+	if _, err := config.HandleCallback(context.Background(), authorization, url.Values{"state": {"forged"}, "code": {"code"}}); !errors.Is(err, ErrOAuth2State) {
+		t.Errorf("error of a forged state = %v, want %v", err, ErrOAuth2State)
+	}
+	if _, err := config.HandleCallback(context.Background(), authorization, url.Values{"state": {"state"}, "code": {"expired"}}); !errors.As(err, &oauth2_error) || oauth2_error.Code != "invalid_grant" || oauth2_error.StatusCode != http.StatusBadRequest {
+		t.Errorf("error of an expired code = %v, want invalid_grant", err)
+	}
+	if len(endpoint.requests) != 1 {
+		t.Errorf("%d token requests, want only the one of the expired code", len(endpoint.requests))
+	}
+
+	token, err := config.HandleCallback(context.Background(), authorization, url.Values{"state": {"state"}, "code": {"code"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.Expired(time.Hour) || !token.Expired(3*time.Hour) {
+		t.Errorf("token = %+v, want access-1 expiring in 2 hours", token)
+	}
+	form := endpoint.requests[1]
+	if form.Get("code_verifier") != "verifier" || form.Get("redirect_uri") != config.RedirectURL || form.Get("client_id") != "client" {
+		t.Errorf("token request = %v, want the code verifier, redirect URL and client ID", form)
+	}
+	if endpoint.username != "" {
+		t.Errorf("a public client sends basic auth of %q", endpoint.username)
+	}
+}
+
+func TestOAuth2ConfidentialClient(t *testing.T) {
+	config, endpoint := new_test_oauth2_config(t)
+	config.ClientSecret = "secret"
+
+	if _, err := config.Exchange(context.Background(), "code", "verifier"); err != nil {
+		t.Fatal(err)
+	}
+	if endpoint.username != "client" || endpoint.password != "secret" {
+		t.Errorf("basic auth = %q:%q, want client:secret", endpoint.username, endpoint.password)
+	}
+	if endpoint.requests[0].Has("client_id") {
+		t.Error("a confidential client sends its ID in the form")
+	}
+}
+
+func TestOAuth2Refresh(t *testing.T) {
+	config, endpoint := new_test_oauth2_config(t)
+	token, _ := config.Exchange(context.Background(), "code", "verifier")
+
+	refreshed, err := config.Refresh(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.AccessToken != "access-2" || refreshed.RefreshToken != "refresh-2" {
+		t.Errorf("refreshed token = %+v, want access-2 and refresh-2", refreshed)
+	}
+	// The old refresh token is used up.
+	if _, err := config.Refresh(context.Background(), token); err == nil {
+		t.Error("no error for a used refresh token")
+	}
+
+	endpoint.keep_refresh_token = true
+	kept, err := config.Refresh(context.Background(), refreshed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.AccessToken != "access-3" || kept.RefreshToken != "refresh-2" {
+		t.Errorf("refreshed token = %+v, want access-3 keeping refresh-2", kept)
+	}
+
+	if _, err := config.Refresh(context.Background(), &OAuth2Token{AccessToken: "access"}); err == nil {
+		t.Error("no error for a token without a refresh token")
+	}
+}
+
+func TestOAuth2TokenSource(t *testing.T) {
+	config, endpoint := new_test_oauth2_config(t)
+	store := &FileTokenStore{Path: filepath.Join(t.TempDir(), "token.json")}
+	token, _ := config.Exchange(context.Background(), "code", "verifier")
+
+	source := config.TokenSource(token, store)
+	got, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != "access-1" {
+		t.Errorf("token = %s, want access-1 which doesn't expire soon", got.AccessToken)
+	}
+	if saved, _ := store.Load(context.Background()); saved == nil || saved.AccessToken != "access-1" {
+		t.Errorf("saved token = %v, want the first token", saved)
+	}
+	if info, err := os.Stat(store.Path); err != nil {
+		t.Error(err)
+	} else if info.Mode().Perm() != 0o600 {
+		t.Errorf("token file mode = %v, want it to be readable only by its owner", info.Mode())
+	}
+
+	// A new source loads the token from the store, and refreshes it since it expires within RefreshBefore.
+	source = config.TokenSource(nil, store)
+	source.RefreshBefore = 3 * time.Hour
+	for i := 0; i < 2; i++ {
+		if got, err = source.Token(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		source.RefreshBefore = time.Minute
+	}
+	if got.AccessToken != "access-2" || len(endpoint.requests) != 2 {
+		t.Errorf("token = %s after %d token requests, want access-2 after a single refresh", got.AccessToken, len(endpoint.requests))
+	}
+	if saved, _ := store.Load(context.Background()); saved == nil || saved.AccessToken != "access-2" || saved.RefreshToken != "refresh-2" {
+		t.Errorf("saved token = %v, want the refreshed one", saved)
+	}
+
+	empty := config.TokenSource(nil, &FileTokenStore{Path: filepath.Join(t.TempDir(), "missing.json")})
+	if _, err := empty.Token(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("error without a token = %v, want %v", err, ErrNoCredentials)
+	}
+}
+
+func TestOAuth2Client(t *testing.T) {
+	config, _ := new_test_oauth2_config(t)
+	token, _ := config.Exchange(context.Background(), "code", "verifier")
+
+	var mu sync.Mutex
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/2/users/me" {
+			w.Write([]byte(`{"data":{"id":"42","name":"Gopher","username":"gopher"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[],"meta":{"result_count":0}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewOAuth2Client(config.TokenSource(token, nil), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetBookmarkedTweets(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The ID of the user is looked up once.
+	want := "[/2/users/me /2/users/42/bookmarks /2/users/42/bookmarks]"
+	if fmt.Sprint(paths) != want {
+		t.Errorf("requests = %v, want %s", paths, want)
+	}
+
+	if _, err := NewOAuth2Client(nil); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error without a token source = %v, want %v", err, ErrInvalidParams)
+	}
+}
@@ -1,16 +1,75 @@
 package twigo
 
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// Returned when a call needs an auth type the client has no credentials for,
+	// like user context endpoints on a client made by NewBearerOnlyClient.
+	ErrNoCredentials = errors.New("twigo: no credentials for the auth type")
+	// Returned when ContextWithOAuth asks for an auth type the endpoint doesn't support.
+	ErrUnsupportedOAuth = errors.New("twigo: auth type is not supported by the endpoint")
+)
+
+// Type of authentication of a request:
+// OAuth_1a is the user context, signed with the access token, OAuth_2 is app-only, using the bearer token,
+// OAuth_2_User is the user context with an OAuth 2.0 access token, see NewOAuth2Client,
+// and OAuth_Default lets every call pick the right one of its endpoint.
 type OAuthType int8
 
 const (
 	OAuth_Default OAuthType = 0
 	OAuth_1a      OAuthType = 1
 	OAuth_2       OAuthType = 2
+	OAuth_2_User  OAuthType = 3
 )
 
-// TODO: Should be completed, for example,
-// Search tweets and Search spaces or muting have OAuth 1 as default,
-// but others, have version 2 as default
-var DefaultOAuthes = map[string]OAuthType{
-	"": OAuth_Default,
+// Default auth type of every endpoint, by the name of the Client method calling it.
+//
+// Deprecated: Use LookupEndpoint, the map is only a copy of the DefaultAuth of the endpoints,
+// changing it has no effect.
+var DefaultOAuthes = default_oauthes()
+
+func default_oauthes() map[string]OAuthType {
+	oauthes := map[string]OAuthType{"": OAuth_Default}
+	for _, endpoint := range endpoint_registry {
+		oauthes[endpoint.Name] = endpoint.DefaultAuth
+	}
+	return oauthes
+}
+
+func (t OAuthType) String() string {
+	switch t {
+	case OAuth_1a:
+		return "OAuth_1a"
+	case OAuth_2:
+		return "OAuth_2"
+	case OAuth_2_User:
+		return "OAuth_2_User"
+	}
+	return "OAuth_Default"
+}
+
+type oauth_type_key struct{}
+
+// Returns a copy of ctx which makes calls bound to it use the auth type,
+// instead of the one picked from their endpoint.
+//
+//	// Search as the user, not the app.
+//	ctx := twigo.ContextWithOAuth(context.Background(), twigo.OAuth_1a)
+//	tweets, err := client.SearchRecentTweetsCtx(ctx, "golang", nil)
+//
+// Calls to endpoints that don't support it fail with ErrUnsupportedOAuth,
+// and if the client has no credentials for it, with ErrNoCredentials.
+// OAuth_Default removes the override.
+func ContextWithOAuth(ctx context.Context, oauth_type OAuthType) context.Context {
+	return context.WithValue(ctx, oauth_type_key{}, oauth_type)
+}
+
+// Returns the auth type set by ContextWithOAuth, if any.
+func oauth_from_context(ctx context.Context) (OAuthType, bool) {
+	oauth_type, ok := ctx.Value(oauth_type_key{}).(OAuthType)
+	return oauth_type, ok && oauth_type != OAuth_Default
 }
@@ -0,0 +1,75 @@
+package twigo
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arshamalh/twigo/query"
+)
+
+// ClientOption configures a Client, pass them to NewClient or NewBearerOnlyClient.
+type ClientOption func(*Client)
+
+// Points the client to another API host, for example a local stand-in server or a proxy.
+//
+// Only the scheme and host (and an optional path prefix) should be passed,
+// version prefixes such as "2/" are added by twigo itself.
+//
+//	twigo.WithBaseURL("http://localhost:8080")
+func WithBaseURL(base_url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(base_url, "/") + "/"
+	}
+}
+
+// Uses the given http.Client for all requests,
+// OAuth 1.0a requests are signed on top of its transport.
+func WithHTTPClient(http_client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = http_client
+	}
+}
+
+// Sets the User-Agent header of every request.
+func WithUserAgent(user_agent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = user_agent
+	}
+}
+
+// Sets a time limit for each request,
+// the http.Client passed by WithHTTPClient is copied and never modified.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// Applies the options and prepares the http client of c.
+func (c *Client) applyOptions(opts []ClientOption) {
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{}
+	}
+
+	if c.timeout > 0 {
+		http_client := *c.httpClient
+		http_client.Timeout = c.timeout
+		c.httpClient = &http_client
+	}
+}
+
+// Sets the access level of the project,
+// so search queries longer than its limit are rejected before they are sent,
+// and so are full-archive requests if it's not the Academic Research level.
+//
+// Queries are not checked if it's not set.
+func WithAccessLevel(level query.AccessLevel) ClientOption {
+	return func(c *Client) {
+		c.accessLevel = &level
+	}
+}
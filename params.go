@@ -0,0 +1,442 @@
+package twigo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arshamalh/twigo/utils"
+)
+
+// Returned when the params of a request are invalid, nothing is sent in this case.
+var ErrInvalidParams = errors.New("twigo: invalid params")
+
+// Parameters are the params of a request,
+// Map implements it, and so do option structs like TweetLookupOptions or SearchOptions.
+type Parameters interface {
+	// Returns the params as a new Map, or an error if they are not valid.
+	ToMap() (Map, error)
+}
+
+// Returns a copy of the map, so the original one is never modified by the client.
+func (m Map) ToMap() (Map, error) {
+	params := make(Map, len(m))
+	for key, value := range m {
+		params[key] = value
+	}
+	return params, nil
+}
+
+// Converts options to a new Map, nil options make an empty one.
+func to_map(options Parameters) (Map, error) {
+	if options == nil {
+		return Map{}, nil
+	}
+	return options.ToMap()
+}
+
+func invalid_params(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidParams, fmt.Sprintf(format, args...))
+}
+
+// Fields and expansions that should be returned with the objects of the response.
+type Fields struct {
+	Expansions  []string
+	TweetFields []string
+	UserFields  []string
+	MediaFields []string
+	PlaceFields []string
+	PollFields  []string
+	ListFields  []string
+	SpaceFields []string
+	// Only Direct Message events have them.
+	DMEventFields []string
+}
+
+func (f *Fields) add_to(params Map) {
+	add := func(key string, values []string) {
+		if len(values) != 0 {
+			params[key] = values
+		}
+	}
+	add("expansions", f.Expansions)
+	add("tweet.fields", f.TweetFields)
+	add("user.fields", f.UserFields)
+	add("media.fields", f.MediaFields)
+	add("place.fields", f.PlaceFields)
+	add("poll.fields", f.PollFields)
+	add("list.fields", f.ListFields)
+	add("space.fields", f.SpaceFields)
+	add("dm_event.fields", f.DMEventFields)
+}
+
+// Adds the time range and the ID range to params, after checking the time range.
+func add_ranges(params Map, start_time, end_time time.Time, since_id, until_id string) error {
+	if !start_time.IsZero() && !end_time.IsZero() && !start_time.Before(end_time) {
+		return invalid_params("start_time must be before end_time")
+	}
+	if !start_time.IsZero() {
+		params["start_time"] = start_time
+	}
+	if !end_time.IsZero() {
+		params["end_time"] = end_time
+	}
+	if since_id != "" {
+		params["since_id"] = since_id
+	}
+	if until_id != "" {
+		params["until_id"] = until_id
+	}
+	return nil
+}
+
+// Adds max_results to params if it's set, after checking it's in the range.
+func add_max_results(params Map, max_results, min, max int) error {
+	if max_results == 0 {
+		return nil
+	}
+	if max_results < min || max_results > max {
+		return invalid_params("max_results must be between %d and %d", min, max)
+	}
+	params["max_results"] = max_results
+	return nil
+}
+
+// Checks max_results of params is at most max, for options shared by endpoints with different limits.
+func check_max_results(params Map, max int) error {
+	if max_results, ok := params["max_results"].(int); ok && max_results > max {
+		return invalid_params("max_results must be at most %d", max)
+	}
+	return nil
+}
+
+// Options of GetTweet and GetTweets.
+type TweetLookupOptions struct {
+	Fields
+}
+
+func (o *TweetLookupOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o != nil {
+		o.Fields.add_to(params)
+	}
+	return params, nil
+}
+
+// Options of GetMe, GetUserByID, GetUserByUsername, GetUsersByIDs and GetUsersByUsernames.
+type UserLookupOptions struct {
+	Fields
+}
+
+func (o *UserLookupOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o != nil {
+		o.Fields.add_to(params)
+	}
+	return params, nil
+}
+
+// Options of SearchRecentTweets and SearchAllTweets.
+type SearchOptions struct {
+	Fields
+	StartTime time.Time
+	EndTime   time.Time
+	SinceID   string
+	UntilID   string
+	// Between 10 and 100 for recent search, and up to 500 for full-archive search.
+	MaxResults int
+	NextToken  string
+	// Either "recency" or "relevancy".
+	SortOrder string
+}
+
+func (o *SearchOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o == nil {
+		return params, nil
+	}
+
+	o.Fields.add_to(params)
+	if err := add_ranges(params, o.StartTime, o.EndTime, o.SinceID, o.UntilID); err != nil {
+		return nil, err
+	}
+	if err := add_max_results(params, o.MaxResults, 10, 500); err != nil {
+		return nil, err
+	}
+	if o.NextToken != "" {
+		params["next_token"] = o.NextToken
+	}
+	if o.SortOrder != "" {
+		if !utils.Contains([]string{"recency", "relevancy"}, o.SortOrder) {
+			return nil, invalid_params("sort_order must be either 'recency' or 'relevancy'")
+		}
+		params["sort_order"] = o.SortOrder
+	}
+	return params, nil
+}
+
+// Options of GetUserTweets, GetUserMentions and GetHomeTimeline.
+type TimelineOptions struct {
+	Fields
+	StartTime  time.Time
+	EndTime    time.Time
+	SinceID    string
+	UntilID    string
+	MaxResults int
+	// Only GetUserTweets and GetHomeTimeline support it, "retweets" and/or "replies".
+	Exclude         []string
+	PaginationToken string
+}
+
+func (o *TimelineOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o == nil {
+		return params, nil
+	}
+
+	o.Fields.add_to(params)
+	if err := add_ranges(params, o.StartTime, o.EndTime, o.SinceID, o.UntilID); err != nil {
+		return nil, err
+	}
+	if err := add_max_results(params, o.MaxResults, 5, 100); err != nil {
+		return nil, err
+	}
+	for _, exclude := range o.Exclude {
+		if !utils.Contains([]string{"retweets", "replies"}, exclude) {
+			return nil, invalid_params("exclude can only contain 'retweets' and 'replies'")
+		}
+	}
+	if len(o.Exclude) != 0 {
+		params["exclude"] = o.Exclude
+	}
+	if o.PaginationToken != "" {
+		params["pagination_token"] = o.PaginationToken
+	}
+	return params, nil
+}
+
+// Options of the other paginated methods, like GetUserFollowers, GetLikingUsers or GetListMembers.
+type PaginationOptions struct {
+	Fields
+	MaxResults      int
+	PaginationToken string
+}
+
+func (o *PaginationOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o == nil {
+		return params, nil
+	}
+
+	o.Fields.add_to(params)
+	if err := add_max_results(params, o.MaxResults, 1, 1000); err != nil {
+		return nil, err
+	}
+	if o.PaginationToken != "" {
+		params["pagination_token"] = o.PaginationToken
+	}
+	return params, nil
+}
+
+// Options of GetAllTweetsCount and GetRecentTweetsCount.
+type CountsOptions struct {
+	StartTime   time.Time
+	EndTime     time.Time
+	SinceID     string
+	UntilID     string
+	Granularity Granularity
+	// Only GetAllTweetsCount is paginated.
+	NextToken string
+}
+
+func (o *CountsOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o == nil {
+		return params, nil
+	}
+
+	if err := add_ranges(params, o.StartTime, o.EndTime, o.SinceID, o.UntilID); err != nil {
+		return nil, err
+	}
+	if o.Granularity != "" {
+		if o.Granularity.Duration() == 0 {
+			return nil, invalid_params("granularity must be 'minute', 'hour' or 'day'")
+		}
+		params["granularity"] = string(o.Granularity)
+	}
+	if o.NextToken != "" {
+		params["next_token"] = o.NextToken
+	}
+	return params, nil
+}
+
+// Options of CreateTweet, only one of Media, Poll and QuoteTweetID can be set.
+type CreateTweetOptions struct {
+	DirectMessageDeepLink string            `json:"direct_message_deep_link,omitempty"`
+	ForSuperFollowersOnly bool              `json:"for_super_followers_only,omitempty"`
+	Geo                   *CreateTweetGeo   `json:"geo,omitempty"`
+	Media                 *CreateTweetMedia `json:"media,omitempty"`
+	Poll                  *CreateTweetPoll  `json:"poll,omitempty"`
+	QuoteTweetID          string            `json:"quote_tweet_id,omitempty"`
+	Reply                 *CreateTweetReply `json:"reply,omitempty"`
+	// Either "mentionedUsers" or "following", everyone can reply if it's empty.
+	ReplySettings string `json:"reply_settings,omitempty"`
+}
+
+type CreateTweetGeo struct {
+	PlaceID string `json:"place_id"`
+}
+
+type CreateTweetMedia struct {
+	// Up to 4 media IDs, see UploadMedia.
+	MediaIDs      []string `json:"media_ids"`
+	TaggedUserIDs []string `json:"tagged_user_ids,omitempty"`
+}
+
+type CreateTweetPoll struct {
+	// 2 to 4 options, each one up to 25 characters.
+	Options []string `json:"options"`
+	// Between 5 and 10080 minutes.
+	DurationMinutes int `json:"duration_minutes"`
+}
+
+type CreateTweetReply struct {
+	InReplyToTweetID    string   `json:"in_reply_to_tweet_id"`
+	ExcludeReplyUserIDs []string `json:"exclude_reply_user_ids,omitempty"`
+}
+
+func (o *CreateTweetOptions) validate() error {
+	attachments := 0
+	if o.Media != nil {
+		attachments++
+		if len(o.Media.MediaIDs) == 0 || len(o.Media.MediaIDs) > 4 {
+			return invalid_params("media needs 1 to 4 media_ids")
+		}
+	}
+	if o.Poll != nil {
+		attachments++
+		if len(o.Poll.Options) < 2 || len(o.Poll.Options) > 4 {
+			return invalid_params("poll needs 2 to 4 options")
+		}
+		for _, option := range o.Poll.Options {
+			if length := len([]rune(option)); length == 0 || length > 25 {
+				return invalid_params("poll options must be 1 to 25 characters")
+			}
+		}
+		if o.Poll.DurationMinutes < 5 || o.Poll.DurationMinutes > 10080 {
+			return invalid_params("poll duration_minutes must be between 5 and 10080")
+		}
+	}
+	if o.QuoteTweetID != "" {
+		attachments++
+	}
+	if attachments > 1 {
+		return invalid_params("only one of media, poll and quote_tweet_id can be set")
+	}
+	if o.Reply != nil && o.Reply.InReplyToTweetID == "" {
+		return invalid_params("reply needs in_reply_to_tweet_id")
+	}
+	if o.Geo != nil && o.Geo.PlaceID == "" {
+		return invalid_params("geo needs place_id")
+	}
+	if o.ReplySettings != "" && !utils.Contains([]string{"mentionedUsers", "following"}, o.ReplySettings) {
+		return invalid_params("reply_settings must be either 'mentionedUsers' or 'following'")
+	}
+	return nil
+}
+
+func (o *CreateTweetOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o == nil {
+		return params, nil
+	}
+
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	// The body is json, so the json form of the struct is exactly what we need.
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &params)
+	return params, err
+}
+
+// Options of GetDMEvents, GetConversationDMEvents and GetParticipantDMEvents.
+type DMEventsOptions struct {
+	Fields
+	// Any of "MessageCreate", "ParticipantsJoin" and "ParticipantsLeave", all of them if it's empty.
+	EventTypes []string
+	// Between 1 and 100.
+	MaxResults      int
+	PaginationToken string
+}
+
+func (o *DMEventsOptions) ToMap() (Map, error) {
+	params := Map{}
+	if o == nil {
+		return params, nil
+	}
+
+	o.Fields.add_to(params)
+	for _, event_type := range o.EventTypes {
+		if !utils.Contains([]string{"MessageCreate", "ParticipantsJoin", "ParticipantsLeave"}, event_type) {
+			return nil, invalid_params("event_types can only contain 'MessageCreate', 'ParticipantsJoin' and 'ParticipantsLeave'")
+		}
+	}
+	if len(o.EventTypes) != 0 {
+		params["event_types"] = o.EventTypes
+	}
+	if err := add_max_results(params, o.MaxResults, 1, 100); err != nil {
+		return nil, err
+	}
+	if o.PaginationToken != "" {
+		params["pagination_token"] = o.PaginationToken
+	}
+	return params, nil
+}
+
+// A Direct Message to send, it needs a text, an attachment, or both.
+type DirectMessage struct {
+	Text string `json:"text,omitempty"`
+	// Only one attachment is supported for now.
+	Attachments []DMAttachment `json:"attachments,omitempty"`
+}
+
+type DMAttachment struct {
+	// See UploadMedia, the media must be uploaded for use in Direct Messages.
+	MediaID string `json:"media_id"`
+}
+
+func (m *DirectMessage) validate() error {
+	if m == nil || (m.Text == "" && len(m.Attachments) == 0) {
+		return invalid_params("message needs text or attachments")
+	}
+	if len(m.Attachments) > 1 {
+		return invalid_params("message can only have one attachment")
+	}
+	for _, attachment := range m.Attachments {
+		if attachment.MediaID == "" {
+			return invalid_params("attachments need media_id")
+		}
+	}
+	return nil
+}
+
+// Returns the body params of a request sending the message.
+func (m *DirectMessage) ToMap() (Map, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	params := Map{}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &params)
+	return params, err
+}
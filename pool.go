@@ -0,0 +1,271 @@
+package twigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// Returned by ClientPool when none of its clients can call an endpoint, or act as a user.
+	ErrNoClient = errors.New("twigo: no client in the pool")
+	// Returned by ClientPool.Pick for endpoints acting as the authenticated user, like CreateTweet or GetHomeTimeline.
+	ErrUserBound = errors.New("twigo: endpoint is bound to a user")
+)
+
+const (
+	// Consecutive failures after which a client of a pool cools down.
+	pool_max_failures = 3
+	// How long a failing client of a pool is skipped.
+	pool_cool_down = time.Minute
+)
+
+// ClientPool holds the clients of many accounts and apps,
+// and spreads the calls among them using their client-side rate limiters.
+//
+// Reads go to the client with the most remaining requests for the endpoint,
+// so the bearer tokens of many apps, or the user contexts of many accounts, are rotated.
+// Writes, and reads of the authenticated user like GetHomeTimeline, must be bound to their account, get its client with ForUser.
+//
+// Clients sharing a bearer token are tracked separately,
+// until the rate limit headers of the responses correct them, so give each app its own token.
+//
+// It's safe for concurrent use.
+type ClientPool struct {
+	mu      sync.Mutex
+	members []*pool_member
+	// Where the next pick starts, so clients with the same budget take turns.
+	next int
+}
+
+type pool_member struct {
+	client    *Client
+	requests  int
+	failures  int
+	last_err  error
+	disabled  bool
+	cool_down time.Time
+}
+
+// The state of a client of a pool.
+type ClientHealth struct {
+	// ID of the user of the client, empty for app-only clients and OAuth 2.0 clients which didn't look it up yet.
+	UserID string
+	// Whether or not the client is used for picks.
+	Healthy bool
+	// The client got a 401, its credentials are revoked or invalid, so it's never picked again.
+	Disabled bool
+	// Number of requests the client sent through the pool.
+	Requests int
+	// Number of consecutive failed requests.
+	Failures  int
+	LastError error
+	// The client is skipped until then, after too many consecutive failures.
+	CoolingDownUntil time.Time
+}
+
+// The state of all clients of a pool.
+type PoolHealth struct {
+	Clients []ClientHealth
+	// Number of healthy clients.
+	Healthy int
+}
+
+// Returns a pool of clients made by NewClient from configs,
+// opts are applied to all of them, after a default WithRateLimiter(RateLimitWait).
+func NewClientPool(configs []*Config, opts ...ClientOption) (*ClientPool, error) {
+	pool := &ClientPool{}
+	for i, config := range configs {
+		client, err := NewClient(config, append([]ClientOption{WithRateLimiter(RateLimitWait)}, opts...)...)
+		if err != nil {
+			return nil, fmt.Errorf("twigo: config %d of the pool: %w", i, err)
+		}
+		pool.Add(client)
+	}
+	return pool, nil
+}
+
+// Adds a client to the pool, like one made by NewOAuth2Client,
+// it should have a rate limiter, otherwise the documented limits are taken as its budget.
+//
+// The pool observes the results of all requests of the client, so add it before using it,
+// a client can be added to more than one pool.
+func (p *ClientPool) Add(client *Client) {
+	member := &pool_member{client: client}
+	// Another pool, or anything else observing the client, keeps observing it.
+	previous := client.observer
+	client.observer = func(err error) {
+		if previous != nil {
+			previous(err)
+		}
+		p.observe(member, err)
+	}
+
+	p.mu.Lock()
+	p.members = append(p.members, member)
+	p.mu.Unlock()
+}
+
+// Updates the health of a member after one of its requests.
+func (p *ClientPool) observe(member *pool_member, err error) {
+	// The request was stopped by the client-side rate limiter or its caller, it says nothing about the client.
+	if errors.Is(err, ErrWouldExceedLimit) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	member.requests++
+	var api_error *APIError
+	switch {
+	case err == nil:
+		member.failures = 0
+		member.last_err = nil
+		return
+	case errors.As(err, &api_error) && api_error.StatusCode == http.StatusUnauthorized:
+		member.disabled = true
+	case errors.As(err, &api_error) && api_error.StatusCode < 500 && api_error.StatusCode != http.StatusTooManyRequests:
+		// The request was wrong, not the client.
+		return
+	}
+
+	member.failures++
+	member.last_err = err
+	if member.failures >= pool_max_failures {
+		member.cool_down = time.Now().Add(pool_cool_down)
+	}
+}
+
+func (m *pool_member) healthy(now time.Time) bool {
+	return !m.disabled && !now.Before(m.cool_down)
+}
+
+// Remaining requests of the member for the endpoint, and when they are refilled.
+func (m *pool_member) budget(endpoint *Endpoint, oauth_type OAuthType) (int, time.Time) {
+	limits, ok := m.client.RateLimitStatus(oauth_type, endpoint.Method, endpoint.Path)
+	if !ok {
+		return endpoint.RateLimit(oauth_type), time.Time{}
+	}
+	return limits.Remaining, time.Unix(limits.ResetTimestamp, 0)
+}
+
+// Returns the healthy client with the most remaining requests for the endpoint a method calls, like "SearchRecentTweets",
+// among the ones with credentials for it. When all of them are out of requests, the one which is refilled first is picked.
+// App-only clients are preferred, so the budgets of the accounts are left for their own calls.
+//
+//	client, err := pool.Pick("GetUserTweets")
+//	tweets, err := client.GetUserTweets(user_id, nil)
+//
+// Endpoints acting as the authenticated user, the ones which aren't GET or don't support app-only auth,
+// return ErrUserBound, since a random account would be used, get the client of the account with ForUser instead.
+func (p *ClientPool) Pick(name string) (*Client, error) {
+	endpoint := find_endpoint(name)
+	if endpoint == nil {
+		return nil, fmt.Errorf("twigo: unknown endpoint %s", name)
+	}
+	if endpoint.Method != "GET" || !endpoint.Supports(OAuth_2) {
+		return nil, fmt.Errorf("%w: %s acts as the authenticated user, use ForUser to get the client of the account", ErrUserBound, name)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var best *pool_member
+	best_app, best_remaining, best_reset := false, 0, time.Time{}
+	for i := range p.members {
+		member := p.members[(p.next+i)%len(p.members)]
+		if !member.healthy(now) {
+			continue
+		}
+		oauth_type, err := member.client.auth_for(context.Background(), endpoint)
+		if err != nil {
+			continue
+		}
+
+		app := oauth_type == OAuth_2
+		remaining, reset := member.budget(endpoint, oauth_type)
+		switch {
+		case best == nil, app && !best_app:
+		case app != best_app:
+			continue
+		case remaining > best_remaining:
+		case remaining == 0 && best_remaining == 0 && reset.Before(best_reset):
+		default:
+			continue
+		}
+		best, best_app, best_remaining, best_reset = member, app, remaining, reset
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: no healthy client can call %s", ErrNoClient, name)
+	}
+
+	p.next++
+	return best.client, nil
+}
+
+// Returns the client acting as the user, for user context calls like CreateTweet,
+// it's returned even if it's not healthy, since no other client can act as the user.
+func (p *ClientPool) ForUser(user_id string) (*Client, error) {
+	if user_id != "" {
+		for _, member := range p.snapshot() {
+			if member.user_id() == user_id {
+				return member.client, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: no client acts as user %s", ErrNoClient, user_id)
+}
+
+// Returns the members of the pool, so they can be used without holding p.mu.
+//
+// The ID of the user is read without p.mu, since the client may be looking it up with GetMe,
+// whose request reports back to the pool.
+func (p *ClientPool) snapshot() []*pool_member {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*pool_member(nil), p.members...)
+}
+
+func (m *pool_member) user_id() string {
+	m.client.userMu.Lock()
+	defer m.client.userMu.Unlock()
+	return m.client.userID
+}
+
+// Returns the state of every client of the pool, in the order they were added.
+func (p *ClientPool) Health() PoolHealth {
+	members := p.snapshot()
+	user_ids := make([]string, len(members))
+	for i, member := range members {
+		user_ids[i] = member.user_id()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	health := PoolHealth{Clients: make([]ClientHealth, 0, len(members))}
+	for i, member := range members {
+		client_health := ClientHealth{
+			UserID:    user_ids[i],
+			Healthy:   member.healthy(now),
+			Disabled:  member.disabled,
+			Requests:  member.requests,
+			Failures:  member.failures,
+			LastError: member.last_err,
+		}
+		if member.cool_down.After(now) {
+			client_health.CoolingDownUntil = member.cool_down
+		}
+		if client_health.Healthy {
+			health.Healthy++
+		}
+		health.Clients = append(health.Clients, client_health)
+	}
+	return health
+}
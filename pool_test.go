@@ -0,0 +1,239 @@
+package twigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// An API server telling apart the clients of a pool by their bearer or access tokens,
+// it answers with the queued statuses of a client first, then with its remaining requests and reset.
+type fake_pool_server struct {
+	mu        sync.Mutex
+	statuses  map[string][]int
+	remaining map[string]int
+	reset     map[string]time.Time
+}
+
+func new_fake_pool_server(t *testing.T) (*fake_pool_server, *httptest.Server) {
+	fake := &fake_pool_server{statuses: map[string][]int{}, remaining: map[string]int{}, reset: map[string]time.Time{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fake_pool_server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if params := oauth1_header_params(r); params["oauth_token"] != "" {
+		token = params["oauth_token"]
+	}
+	if statuses := f.statuses[token]; len(statuses) != 0 {
+		f.statuses[token] = statuses[1:]
+		w.WriteHeader(statuses[0])
+		return
+	}
+
+	remaining, ok := f.remaining[token]
+	if !ok {
+		remaining = 100
+	}
+	reset, ok := f.reset[token]
+	if !ok {
+		reset = time.Now().Add(rate_limit_window)
+	}
+	w.Header().Set("X-Rate-Limit-Limit", "900")
+	w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if strings.HasPrefix(r.URL.Path, "/2/users/") {
+		w.Write([]byte(`{"data":[{"id":"1","text":"hi"}],"meta":{"result_count":1}}`))
+		return
+	}
+	w.Write([]byte(`{"data":{"id":"1","text":"hi"}}`))
+}
+
+func new_test_pool(t *testing.T, server *httptest.Server, user_ids ...string) *ClientPool {
+	configs := []*Config{}
+	for _, user_id := range user_ids {
+		configs = append(configs, &Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: user_id + "-token", AccessSecret: "secret"})
+	}
+	pool, err := NewClientPool(configs, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// Returns the user ID of the picked client, or "app" for an app-only one.
+func pick_user(t *testing.T, pool *ClientPool, name string) string {
+	client, err := pool.Pick(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.authorizedClient == nil {
+		return "app"
+	}
+	return client.userID
+}
+
+func TestPoolPick(t *testing.T) {
+	fake, server := new_fake_pool_server(t)
+	pool := new_test_pool(t, server, "1", "2")
+
+	// Clients with the same budget take turns.
+	if first, second := pick_user(t, pool, "GetUserTweets"), pick_user(t, pool, "GetUserTweets"); first == second {
+		t.Errorf("picks = %s and %s, want both clients", first, second)
+	}
+
+	// The client with more remaining requests is picked.
+	fake.remaining["1-token"], fake.remaining["2-token"] = 10, 100
+	for _, user_id := range []string{"1", "2"} {
+		client, _ := pool.ForUser(user_id)
+		if _, err := client.GetUserTweets("42", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if picked := pick_user(t, pool, "GetUserTweets"); picked != "2" {
+			t.Errorf("pick %d = %s, want 2 with more remaining requests", i, picked)
+		}
+	}
+
+	// When all of them are out of requests, the one refilled first is picked.
+	fake.remaining["1-token"], fake.remaining["2-token"] = 0, 0
+	fake.reset["1-token"], fake.reset["2-token"] = time.Now().Add(10*time.Minute), time.Now().Add(5*time.Minute)
+	for _, user_id := range []string{"1", "2"} {
+		client, _ := pool.ForUser(user_id)
+		client.GetUserTweets("42", nil)
+	}
+	if picked := pick_user(t, pool, "GetUserTweets"); picked != "2" {
+		t.Errorf("pick = %s, want 2 which is refilled first", picked)
+	}
+
+	// App-only clients are preferred, even with fewer remaining requests.
+	app, _ := NewBearerOnlyClient("app", WithBaseURL(server.URL), WithRateLimiter(RateLimitWait))
+	pool.Add(app)
+	fake.remaining["app"] = 1
+	app.GetUserTweets("42", nil)
+	if picked := pick_user(t, pool, "GetUserTweets"); picked != "app" {
+		t.Errorf("pick = %s, want the app-only client", picked)
+	}
+}
+
+func TestPoolUserBound(t *testing.T) {
+	_, server := new_fake_pool_server(t)
+	pool := new_test_pool(t, server, "1", "2")
+
+	for _, name := range []string{"CreateTweet", "GetHomeTimeline"} {
+		if _, err := pool.Pick(name); !errors.Is(err, ErrUserBound) {
+			t.Errorf("error of picking for %s = %v, want %v", name, err, ErrUserBound)
+		}
+	}
+	if _, err := pool.Pick("NoSuchEndpoint"); err == nil || errors.Is(err, ErrUserBound) {
+		t.Errorf("error of picking for an unknown endpoint = %v", err)
+	}
+
+	client, err := pool.ForUser("2")
+	if err != nil || client.userID != "2" {
+		t.Errorf("ForUser(2) = %v, %v, want the client of user 2", client, err)
+	}
+	if _, err := pool.ForUser("3"); !errors.Is(err, ErrNoClient) {
+		t.Errorf("error of ForUser(3) = %v, want %v", err, ErrNoClient)
+	}
+	if _, err := (&ClientPool{}).Pick("GetTweet"); !errors.Is(err, ErrNoClient) {
+		t.Errorf("error of picking from an empty pool = %v, want %v", err, ErrNoClient)
+	}
+}
+
+func TestPoolHealth(t *testing.T) {
+	fake, server := new_fake_pool_server(t)
+	pool := new_test_pool(t, server, "1", "2")
+	first, _ := pool.ForUser("1")
+	second, _ := pool.ForUser("2")
+
+	// Client errors are the fault of the request, not the client.
+	fake.statuses["1-token"] = []int{http.StatusNotFound}
+	first.GetTweet("1", nil)
+	if health := pool.Health().Clients[0]; health.Requests != 1 || health.Failures != 0 || !health.Healthy {
+		t.Errorf("health after a 404 = %+v, want a healthy client", health)
+	}
+
+	// Consecutive server errors cool the client down.
+	fake.statuses["1-token"] = []int{503, 502, 500}
+	for i := 0; i < 3; i++ {
+		first.GetTweet("1", nil)
+	}
+	health := pool.Health()
+	if client := health.Clients[0]; client.Healthy || client.Failures != 3 || client.CoolingDownUntil.IsZero() || !errors.Is(client.LastError, ErrServerError) {
+		t.Errorf("health after 3 server errors = %+v, want a cooling down client", client)
+	}
+	if health.Healthy != 1 {
+		t.Errorf("%d healthy clients, want 1", health.Healthy)
+	}
+	for i := 0; i < 3; i++ {
+		if picked := pick_user(t, pool, "GetTweet"); picked != "2" {
+			t.Errorf("pick = %s, want 2 while 1 cools down", picked)
+		}
+	}
+
+	// A 401 disables the client for good.
+	fake.statuses["2-token"] = []int{http.StatusUnauthorized}
+	second.GetTweet("1", nil)
+	if client := pool.Health().Clients[1]; !client.Disabled || client.Healthy {
+		t.Errorf("health after a 401 = %+v, want a disabled client", client)
+	}
+	if _, err := pool.Pick("GetTweet"); !errors.Is(err, ErrNoClient) {
+		t.Errorf("error of picking without healthy clients = %v, want %v", err, ErrNoClient)
+	}
+	// The client of a user is still given, since no other client can act as the user.
+	if client, err := pool.ForUser("2"); client != second || err != nil {
+		t.Errorf("ForUser(2) = %v, %v, want the disabled client", client, err)
+	}
+}
+
+func TestPoolHealthIgnoresLocalErrors(t *testing.T) {
+	fake, server := new_fake_pool_server(t)
+	pool, _ := NewClientPool(
+		[]*Config{{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "1-token", AccessSecret: "secret"}},
+		WithBaseURL(server.URL), WithRateLimiter(RateLimitFail),
+	)
+	client, _ := pool.ForUser("1")
+
+	fake.remaining["1-token"] = 0
+	if _, err := client.GetTweet("1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTweet("1", nil); !errors.Is(err, ErrWouldExceedLimit) {
+		t.Fatalf("error = %v, want %v", err, ErrWouldExceedLimit)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetUserTweetsCtx(ctx, "42", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want %v", err, context.Canceled)
+	}
+
+	if health := pool.Health().Clients[0]; health.Requests != 1 || health.Failures != 0 || health.LastError != nil {
+		t.Errorf("health = %+v, want only the sent request", health)
+	}
+}
+
+func TestPoolObserverChain(t *testing.T) {
+	_, server := new_fake_pool_server(t)
+	pool := new_test_pool(t, server, "1")
+	client, _ := pool.ForUser("1")
+	another := &ClientPool{}
+	another.Add(client)
+
+	client.GetTweet("1", nil)
+	if pool.Health().Clients[0].Requests != 1 || another.Health().Clients[0].Requests != 1 {
+		t.Errorf("requests = %d and %d, want both pools to observe the request", pool.Health().Clients[0].Requests, another.Health().Clients[0].Requests)
+	}
+}
@@ -0,0 +1,415 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A malformed query, reported by Parse.
+type SyntaxError struct {
+	// Offset of the error in the query, in bytes.
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query: syntax error at %d: %s", e.Pos, e.Msg)
+}
+
+// A likely mistake in a query, reported by Lint.
+type Issue struct {
+	// Offset of the issue in the query, in bytes.
+	Pos     int
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%d: %s", i.Pos, i.Message)
+}
+
+// Operators taking a value, and whether or not they must be combined with a standalone operator.
+var value_operators = map[string]bool{
+	"from:":                 false,
+	"to:":                   false,
+	"url:":                  false,
+	"retweets_of:":          false,
+	"context:":              false,
+	"entity:":               false,
+	"conversation_id:":      false,
+	"in_reply_to_tweet_id:": false,
+	"retweets_of_tweet_id:": false,
+	"quotes_of_tweet_id:":   false,
+	"list:":                 false,
+	"place:":                false,
+	"place_country:":        false,
+	"point_radius:":         false,
+	"bounding_box:":         false,
+	"bio:":                  false,
+	"bio_name:":             false,
+	"bio_location:":         false,
+	"lang:":                 true,
+	"sample:":               true,
+}
+
+// The is: and has: operators, all of them must be combined with a standalone operator.
+var flag_operators = map[string]bool{
+	"is:retweet":   true,
+	"is:reply":     true,
+	"is:quote":     true,
+	"is:verified":  true,
+	"is:nullcast":  true,
+	"has:media":    true,
+	"has:images":   true,
+	"has:videos":   true,
+	"has:links":    true,
+	"has:hashtags": true,
+	"has:cashtags": true,
+	"has:mentions": true,
+	"has:geo":      true,
+}
+
+type token_kind int
+
+const (
+	token_end token_kind = iota
+	token_word
+	token_or
+	token_not
+	token_open
+	token_close
+)
+
+type token struct {
+	kind token_kind
+	text string
+	pos  int
+}
+
+func is_space(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func tokenize(s string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case is_space(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: token_open, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: token_close, text: ")", pos: i})
+			i++
+		case c == '-':
+			if i+1 == len(s) || is_space(s[i+1]) || s[i+1] == ')' {
+				return nil, &SyntaxError{i, "nothing to negate after -"}
+			}
+			tokens = append(tokens, token{kind: token_not, text: "-", pos: i})
+			i++
+		default:
+			end, err := scan_word(s, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := token_word
+			if s[i:end] == "OR" {
+				kind = token_or
+			}
+			tokens = append(tokens, token{kind: kind, text: s[i:end], pos: i})
+			i = end
+		}
+	}
+	return append(tokens, token{kind: token_end, pos: len(s)}), nil
+}
+
+// Returns the end of the word starting at start, quoted parts and [...] values are read as a whole.
+func scan_word(s string, start int) (int, error) {
+	i := start
+	for i < len(s) {
+		switch c := s[i]; {
+		case is_space(c) || c == '(' || c == ')':
+			return i, nil
+		case c == '"':
+			end, err := scan_quoted(s, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case c == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return 0, &SyntaxError{i, "[ is never closed"}
+			}
+			i += end + 1
+		default:
+			i++
+		}
+	}
+	return i, nil
+}
+
+// Returns the end of the quoted part starting at start.
+func scan_quoted(s string, start int) (int, error) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, &SyntaxError{start, "quote is never closed"}
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	// Groups that were written in parentheses.
+	parenthesized map[Query]bool
+	issues        []Issue
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != token_end {
+		p.i++
+	}
+	return t
+}
+
+// query = and { "OR" and }
+func (p *parser) parse_or() (Query, error) {
+	start := p.peek().pos
+	first, err := p.parse_and()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []Query{first}
+	for p.peek().kind == token_or {
+		or := p.next()
+		if kind := p.peek().kind; kind == token_end || kind == token_close || kind == token_or {
+			return nil, &SyntaxError{or.pos, "OR needs a query on both sides"}
+		}
+		next, err := p.parse_and()
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, next)
+	}
+	if len(queries) == 1 {
+		return first, nil
+	}
+
+	for _, q := range queries {
+		if g, ok := q.(*group); ok && !g.or && !p.parenthesized[g] {
+			p.issues = append(p.issues, Issue{g.pos, fmt.Sprintf(
+				"AND binds tighter than OR, so this is read as (%s), add parentheses to make it explicit", g,
+			)})
+		}
+	}
+	return &group{or: true, queries: queries, pos: start}, nil
+}
+
+// and = unary { unary }
+func (p *parser) parse_and() (Query, error) {
+	start := p.peek()
+	queries := []Query{}
+	for {
+		if kind := p.peek().kind; kind == token_end || kind == token_close || kind == token_or {
+			break
+		}
+		q, err := p.parse_unary()
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+
+	switch len(queries) {
+	case 0:
+		switch start.kind {
+		case token_or:
+			return nil, &SyntaxError{start.pos, "OR needs a query on both sides"}
+		case token_close:
+			return nil, &SyntaxError{start.pos, "unbalanced parentheses, ) is never opened"}
+		}
+		return nil, &SyntaxError{start.pos, "empty query"}
+	case 1:
+		return queries[0], nil
+	}
+	return &group{queries: queries, pos: start.pos}, nil
+}
+
+// unary = [ "-" ] primary
+func (p *parser) parse_unary() (Query, error) {
+	if p.peek().kind != token_not {
+		return p.parse_primary()
+	}
+
+	minus := p.next()
+	switch p.peek().kind {
+	case token_not:
+		return nil, &SyntaxError{minus.pos, "double negation"}
+	case token_or:
+		return nil, &SyntaxError{minus.pos, "OR can't be negated"}
+	}
+	q, err := p.parse_primary()
+	if err != nil {
+		return nil, err
+	}
+	return &not{query: q, pos: minus.pos}, nil
+}
+
+// primary = "(" query ")" | word
+func (p *parser) parse_primary() (Query, error) {
+	t := p.next()
+	switch t.kind {
+	case token_open:
+		if p.peek().kind == token_close {
+			return nil, &SyntaxError{t.pos, "empty parentheses"}
+		}
+		q, err := p.parse_or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != token_close {
+			return nil, &SyntaxError{t.pos, "unbalanced parentheses, ( is never closed"}
+		}
+		p.parenthesized[q] = true
+		return q, nil
+	case token_word:
+		return new_term(t.text, t.pos), nil
+	}
+	return nil, &SyntaxError{t.pos, fmt.Sprintf("unexpected %q", t.text)}
+}
+
+// Makes a keyword, a phrase or an operator out of a word of the query.
+func new_term(text string, pos int) *term {
+	if strings.HasPrefix(text, `"`) {
+		return &term{text: text, pos: pos}
+	}
+	if len(text) > 1 && strings.ContainsAny(text[:1], "#@$") {
+		return &term{text: text, operator: text[:1], pos: pos}
+	}
+
+	// "https://..." is a keyword, not an operator.
+	if i := strings.IndexByte(text, ':'); i > 0 && i < len(text)-1 && !strings.HasPrefix(text[i+1:], "//") {
+		name := text[:i+1]
+		if conjunction, ok := value_operators[name]; ok {
+			return &term{text: text, operator: name, conjunction: conjunction, pos: pos}
+		}
+		if name == "is:" || name == "has:" {
+			return &term{text: text, operator: text, conjunction: true, unknown: !flag_operators[text], pos: pos}
+		}
+		return &term{text: text, operator: name, unknown: true, pos: pos}
+	}
+
+	return &term{text: text, pos: pos}
+}
+
+func parse(s string) (Query, []Issue, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &parser{tokens: tokens, parenthesized: map[Query]bool{}}
+	q, err := p.parse_or()
+	if err != nil {
+		return nil, nil, err
+	}
+	if t := p.peek(); t.kind != token_end {
+		return nil, nil, &SyntaxError{t.pos, "unbalanced parentheses, ) is never opened"}
+	}
+	return q, p.issues, nil
+}
+
+// Parses a query or a stream rule in the v2 syntax,
+// the result can be validated with Validate, or combined with other queries.
+//
+// A *SyntaxError is returned if the query is malformed.
+func Parse(s string) (Query, error) {
+	q, _, err := parse(s)
+	return q, err
+}
+
+// Parses the query and returns it in a normalized form,
+// with single spaces, and parentheses only where they are needed or where AND and OR are mixed.
+//
+//	query.Format("a  b OR ((c))") // (a b) OR c
+func Format(s string) (string, error) {
+	q, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return q.String(), nil
+}
+
+// Parses the query and looks for likely mistakes,
+// like unknown operators, standalone negations, or mixing AND and OR without parentheses.
+//
+// A *SyntaxError is returned if the query is malformed, for example if parentheses are unbalanced.
+func Lint(s string) ([]Issue, error) {
+	q, issues, err := parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	q.walk(func(q Query) error {
+		t, ok := q.(*term)
+		if !ok {
+			return nil
+		}
+		switch {
+		case t.unknown:
+			issues = append(issues, Issue{t.pos, fmt.Sprintf("unknown operator %s", t.operator)})
+		case t.text == "or":
+			issues = append(issues, Issue{t.pos, `"or" is matched as a keyword, use OR to match any of the queries`})
+		case t.text == "AND" || t.text == "and":
+			issues = append(issues, Issue{t.pos, fmt.Sprintf("%q is matched as a keyword, queries separated by spaces are already ANDed", t.text)})
+		}
+		return nil
+	})
+	lint_standalone(q, &issues)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Pos < issues[j].Pos
+	})
+	return issues, nil
+}
+
+// Reports the parts of q that can't be used on their own.
+func lint_standalone(q Query, issues *[]Issue) {
+	if q.standalone() {
+		return
+	}
+
+	switch q := q.(type) {
+	case *not:
+		*issues = append(*issues, Issue{q.pos, fmt.Sprintf("standalone negation %s, combine it with a positive query", q)})
+	case *term:
+		*issues = append(*issues, Issue{q.pos, fmt.Sprintf("%s can't be used alone, combine it with a standalone operator", q)})
+	case *group:
+		if q.or {
+			// Each side of an OR must stand on its own.
+			for _, sub := range q.queries {
+				lint_standalone(sub, issues)
+			}
+			return
+		}
+		for _, sub := range q.queries {
+			if _, ok := sub.(*not); !ok {
+				*issues = append(*issues, Issue{q.pos, fmt.Sprintf("(%s) has no standalone operator, add a keyword or an operator like from:", q)})
+				return
+			}
+		}
+		*issues = append(*issues, Issue{q.pos, fmt.Sprintf("(%s) only has negations, combine them with a positive query", q)})
+	}
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"golang", "golang"},
+		{"  golang   lang:en  ", "golang lang:en"},
+		{"a b OR c", "(a b) OR c"},
+		{"a (b OR c)", "a (b OR c)"},
+		{"((a))", "a"},
+		{`"hello world" from:twitterdev`, `"hello world" from:twitterdev`},
+		{"-is:retweet #go", "-is:retweet #go"},
+		{"-(a OR b) c", "-(a OR b) c"},
+		{"in_reply_to_tweet_id:123", "in_reply_to_tweet_id:123"},
+		{"retweets_of_tweet_id:1 OR quotes_of_tweet_id:2", "retweets_of_tweet_id:1 OR quotes_of_tweet_id:2"},
+		{"point_radius:[2.355128 48.861118 16km]", "point_radius:[2.355128 48.861118 16km]"},
+	}
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", test.query, err)
+			continue
+		}
+		if got := q.String(); got != test.want {
+			t.Errorf("Parse(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []string{
+		"",
+		"(a OR b",
+		"a OR b)",
+		`"unterminated`,
+		"a OR",
+		"()",
+	}
+	for _, query := range tests {
+		_, err := Parse(query)
+		var syntax_error *SyntaxError
+		if !errors.As(err, &syntax_error) {
+			t.Errorf("Parse(%q) error = %v, want a *SyntaxError", query, err)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		query string
+		// Substrings of the expected issues, in order.
+		want []string
+	}{
+		{"from:twitterdev -is:retweet", nil},
+		{"golang foo:bar", []string{"unknown operator foo:"}},
+		{"golang or rust", []string{`"or" is matched as a keyword`}},
+		{"golang AND rust", []string{`"AND" is matched as a keyword`}},
+		{"-is:retweet lang:en", []string{"has no standalone operator"}},
+	}
+	for _, test := range tests {
+		issues, err := Lint(test.query)
+		if err != nil {
+			t.Errorf("Lint(%q) error: %v", test.query, err)
+			continue
+		}
+		if len(issues) != len(test.want) {
+			t.Errorf("Lint(%q) = %v, want %d issues", test.query, issues, len(test.want))
+			continue
+		}
+		for i, issue := range issues {
+			if !strings.Contains(issue.Message, test.want[i]) {
+				t.Errorf("Lint(%q) issue %d = %q, want it to contain %q", test.query, i, issue.Message, test.want[i])
+			}
+		}
+	}
+}
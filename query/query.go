@@ -0,0 +1,409 @@
+// Package query builds search queries and filtered stream rules in the Twitter API v2 syntax.
+//
+//	q := query.And(
+//		query.Or(query.Keyword("golang"), query.Hashtag("go")),
+//		query.Lang("en"),
+//		query.Not(query.IsRetweet()),
+//	)
+//	s, err := query.Build(q, query.Elevated)
+//	// (golang OR #go) lang:en -is:retweet
+//
+// Build checks the operators are available at the access level, and the query fits in its length limit.
+//
+// Existing queries, like rules pasted into a config file, can be checked with Parse and Lint:
+//
+//	issues, err := query.Lint("from:twitterdev OR -is:retweet lang:en")
+//	// err is a *SyntaxError if the query is malformed,
+//	// issues are likely mistakes, like "-is:retweet lang:en" which has no standalone operator.
+//
+// https://developer.twitter.com/en/docs/twitter-api/tweets/search/integrate/build-a-query
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Query is a whole search query, or a part of it.
+type Query interface {
+	// Returns the query in the v2 syntax.
+	String() string
+	// Whether or not the query can be used on its own,
+	// operators like is:retweet or lang: must be combined with a standalone one.
+	standalone() bool
+	// Calls visit for this query and all of its sub-queries.
+	walk(visit func(q Query) error) error
+}
+
+// A keyword, a quoted phrase or an operator.
+type term struct {
+	text string
+	// Name of the operator, like "from:" or "has:media", empty for keywords and phrases.
+	operator    string
+	conjunction bool
+	// Set by Parse for operators it doesn't know.
+	unknown bool
+	check   func() error
+	pos     int
+}
+
+func (t *term) String() string {
+	return t.text
+}
+
+func (t *term) standalone() bool {
+	return !t.conjunction
+}
+
+func (t *term) walk(visit func(q Query) error) error {
+	if t.check != nil {
+		if err := t.check(); err != nil {
+			return err
+		}
+	}
+	return visit(t)
+}
+
+// Queries joined by AND (a space) or OR.
+type group struct {
+	or      bool
+	queries []Query
+	// Wrapped in parentheses even where it's not needed, see Group.
+	forced bool
+	pos    int
+}
+
+func (g *group) String() string {
+	parts := make([]string, 0, len(g.queries))
+	for _, q := range g.queries {
+		if q.String() == "" {
+			continue
+		}
+		if sub, ok := q.(*group); ok && sub.needs_parentheses(g.or) {
+			parts = append(parts, "("+sub.String()+")")
+		} else {
+			parts = append(parts, q.String())
+		}
+	}
+	if g.or {
+		return strings.Join(parts, " OR ")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Whether or not g needs parentheses inside a group of the given kind,
+// AND binds tighter than OR, but mixed groups are always wrapped to avoid precedence surprises.
+func (g *group) needs_parentheses(parent_or bool) bool {
+	if len(g.queries) < 2 {
+		return g.forced
+	}
+	return g.forced || g.or != parent_or
+}
+
+func (g *group) standalone() bool {
+	if len(g.queries) == 0 {
+		return false
+	}
+	for _, q := range g.queries {
+		// One standalone query is enough for AND, all of them must be standalone for OR.
+		if q.standalone() != g.or {
+			return !g.or
+		}
+	}
+	return g.or
+}
+
+func (g *group) walk(visit func(q Query) error) error {
+	if err := visit(g); err != nil {
+		return err
+	}
+	for _, q := range g.queries {
+		if err := q.walk(visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A negated query.
+type not struct {
+	query Query
+	pos   int
+}
+
+func (n *not) String() string {
+	if g, ok := n.query.(*group); ok && len(g.queries) > 1 {
+		return "-(" + g.String() + ")"
+	}
+	return "-" + n.query.String()
+}
+
+func (n *not) standalone() bool {
+	return false
+}
+
+func (n *not) walk(visit func(q Query) error) error {
+	if err := visit(n); err != nil {
+		return err
+	}
+	return n.query.walk(visit)
+}
+
+// Matches Tweets matching all of the queries.
+func And(queries ...Query) Query {
+	return &group{queries: queries}
+}
+
+// Matches Tweets matching any of the queries.
+func Or(queries ...Query) Query {
+	return &group{or: true, queries: queries}
+}
+
+// Matches Tweets not matching the query, it can't be used on its own.
+func Not(q Query) Query {
+	return &not{query: q}
+}
+
+// Wraps the query in parentheses, even where they are not needed.
+func Group(q Query) Query {
+	if g, ok := q.(*group); ok {
+		return &group{or: g.or, queries: g.queries, forced: true}
+	}
+	return &group{queries: []Query{q}, forced: true}
+}
+
+// Matches a keyword, it's quoted if it contains spaces or special characters.
+func Keyword(keyword string) Query {
+	return &term{text: quote_if_needed(keyword)}
+}
+
+// Matches the exact phrase, it's always quoted.
+func Phrase(phrase string) Query {
+	return &term{text: quote(phrase)}
+}
+
+// Quotes text, escaping the quotes in it.
+func quote(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `\"`) + `"`
+}
+
+// Quotes text if it can't be used as a single keyword.
+func quote_if_needed(text string) string {
+	if text == "" || text == "OR" || text == "AND" ||
+		strings.ContainsAny(text, " \t\n\"():") ||
+		strings.ContainsAny(text[:1], "-#@$") {
+		return quote(text)
+	}
+	return text
+}
+
+func operator(name, value string) *term {
+	return &term{text: name + quote_if_needed(value), operator: name}
+}
+
+func conjunction_operator(name string) *term {
+	return &term{text: name, operator: name, conjunction: true}
+}
+
+// Matches Tweets containing the hashtag, with or without the leading "#".
+func Hashtag(hashtag string) Query {
+	return &term{text: "#" + strings.TrimPrefix(hashtag, "#"), operator: "#"}
+}
+
+// Matches Tweets mentioning the username, with or without the leading "@".
+func Mention(username string) Query {
+	return &term{text: "@" + strings.TrimPrefix(username, "@"), operator: "@"}
+}
+
+// Matches Tweets containing the cashtag, with or without the leading "$".
+func Cashtag(cashtag string) Query {
+	return &term{text: "$" + strings.TrimPrefix(cashtag, "$"), operator: "$"}
+}
+
+// Matches Tweets sent by the user, a username or a user ID.
+func From(user string) Query {
+	return operator("from:", strings.TrimPrefix(user, "@"))
+}
+
+// Matches Tweets replying to the user, a username or a user ID.
+func To(user string) Query {
+	return operator("to:", strings.TrimPrefix(user, "@"))
+}
+
+// Matches Retweets of the user's Tweets, a username or a user ID.
+func RetweetsOf(user string) Query {
+	return operator("retweets_of:", strings.TrimPrefix(user, "@"))
+}
+
+// Matches Tweets containing the URL, or a part of it.
+func URL(url string) Query {
+	return operator("url:", url)
+}
+
+// Matches Tweets of the conversation.
+func ConversationID(conversation_id string) Query {
+	return operator("conversation_id:", conversation_id)
+}
+
+// Matches the direct replies to the Tweet.
+func InReplyToTweetID(tweet_id string) Query {
+	return operator("in_reply_to_tweet_id:", tweet_id)
+}
+
+// Matches the Retweets of the Tweet.
+func RetweetsOfTweetID(tweet_id string) Query {
+	return operator("retweets_of_tweet_id:", tweet_id)
+}
+
+// Matches the Quote Tweets of the Tweet.
+func QuotesOfTweetID(tweet_id string) Query {
+	return operator("quotes_of_tweet_id:", tweet_id)
+}
+
+// Matches Tweets with the entity, like a person or a place name.
+func Entity(entity string) Query {
+	return operator("entity:", entity)
+}
+
+// Matches Tweets with the context annotation, like "10.799022225751871488" or "domain_id.*".
+func Context(context string) Query {
+	return operator("context:", context)
+}
+
+// Matches Tweets of the List members.
+func List(list_id string) Query {
+	return operator("list:", list_id)
+}
+
+// Matches Tweets tagged with the place, either a name or a place ID.
+func Place(place string) Query {
+	return operator("place:", place)
+}
+
+// Matches Tweets tagged with a place in the country, using its ISO alpha-2 code.
+func PlaceCountry(country_code string) Query {
+	return operator("place_country:", country_code)
+}
+
+// Matches Tweets of users having the keyword or phrase in their bio.
+func Bio(text string) Query {
+	return operator("bio:", text)
+}
+
+// Matches Tweets of users having the keyword in their name.
+func BioName(name string) Query {
+	return operator("bio_name:", name)
+}
+
+// Matches Tweets of users having the location in their profile.
+func BioLocation(location string) Query {
+	return operator("bio_location:", location)
+}
+
+// Matches Tweets tagged with a location within the radius of the point,
+// radius is a number followed by "mi" or "km", up to 25 miles.
+func PointRadius(longitude, latitude float64, radius string) Query {
+	t := &term{
+		text:     "point_radius:[" + format_float(longitude) + " " + format_float(latitude) + " " + radius + "]",
+		operator: "point_radius:",
+	}
+	t.check = func() error {
+		if err := check_coordinates(longitude, latitude); err != nil {
+			return err
+		}
+		return check_radius(radius)
+	}
+	return t
+}
+
+// Matches Tweets tagged with a location within the box, each side can be up to 25 miles.
+func BoundingBox(west_longitude, south_latitude, east_longitude, north_latitude float64) Query {
+	t := &term{
+		text: "bounding_box:[" + format_float(west_longitude) + " " + format_float(south_latitude) + " " +
+			format_float(east_longitude) + " " + format_float(north_latitude) + "]",
+		operator: "bounding_box:",
+	}
+	t.check = func() error {
+		if err := check_coordinates(west_longitude, south_latitude); err != nil {
+			return err
+		}
+		return check_coordinates(east_longitude, north_latitude)
+	}
+	return t
+}
+
+func format_float(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Matches Tweets in the language, using its BCP 47 code like "en", it must be combined with a standalone operator.
+func Lang(language string) Query {
+	t := conjunction_operator("lang:")
+	t.text += language
+	return t
+}
+
+// Matches Retweets, it must be combined with a standalone operator.
+func IsRetweet() Query {
+	return conjunction_operator("is:retweet")
+}
+
+// Matches replies, it must be combined with a standalone operator.
+func IsReply() Query {
+	return conjunction_operator("is:reply")
+}
+
+// Matches Quote Tweets, it must be combined with a standalone operator.
+func IsQuote() Query {
+	return conjunction_operator("is:quote")
+}
+
+// Matches Tweets of verified users, it must be combined with a standalone operator.
+func IsVerified() Query {
+	return conjunction_operator("is:verified")
+}
+
+// Matches Tweets not promoted by ads, use it with Not, it must be combined with a standalone operator.
+func IsNullcast() Query {
+	return conjunction_operator("is:nullcast")
+}
+
+// Matches Tweets with media, it must be combined with a standalone operator.
+func HasMedia() Query {
+	return conjunction_operator("has:media")
+}
+
+// Matches Tweets with images, it must be combined with a standalone operator.
+func HasImages() Query {
+	return conjunction_operator("has:images")
+}
+
+// Matches Tweets with native videos, it must be combined with a standalone operator.
+func HasVideos() Query {
+	return conjunction_operator("has:videos")
+}
+
+// Matches Tweets with links, it must be combined with a standalone operator.
+func HasLinks() Query {
+	return conjunction_operator("has:links")
+}
+
+// Matches Tweets with hashtags, it must be combined with a standalone operator.
+func HasHashtags() Query {
+	return conjunction_operator("has:hashtags")
+}
+
+// Matches Tweets with cashtags, it must be combined with a standalone operator.
+func HasCashtags() Query {
+	return conjunction_operator("has:cashtags")
+}
+
+// Matches Tweets mentioning users, it must be combined with a standalone operator.
+func HasMentions() Query {
+	return conjunction_operator("has:mentions")
+}
+
+// Matches Tweets with geo data, it must be combined with a standalone operator.
+func HasGeo() Query {
+	return conjunction_operator("has:geo")
+}
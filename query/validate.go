@@ -0,0 +1,176 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// The query uses an operator which is not available at the access level.
+	ErrNotAvailable = errors.New("query: operator not available")
+	// The query is longer than the limit of the access level.
+	ErrTooLong = errors.New("query: too long")
+	// The query has no standalone operator, like a query made of negations or is:retweet only.
+	ErrNotStandalone = errors.New("query: needs a standalone operator")
+	// The query is empty, or an operator has an invalid value.
+	ErrInvalid = errors.New("query: invalid")
+)
+
+// AccessLevel of the project, it decides the available operators and the length of queries.
+type AccessLevel int
+
+const (
+	Essential AccessLevel = iota
+	Elevated
+	Academic
+)
+
+func (level AccessLevel) String() string {
+	switch level {
+	case Essential:
+		return "Essential"
+	case Elevated:
+		return "Elevated"
+	case Academic:
+		return "Academic Research"
+	}
+	return fmt.Sprintf("AccessLevel(%d)", int(level))
+}
+
+// Maximum length of a query or a stream rule at the access level.
+func (level AccessLevel) MaxLength() int {
+	if level >= Academic {
+		return 1024
+	}
+	return 512
+}
+
+// Maximum number of filtered stream rules at the access level.
+func (level AccessLevel) MaxRules() int {
+	switch {
+	case level >= Academic:
+		return 1000
+	case level == Elevated:
+		return 25
+	}
+	return 5
+}
+
+// Operators only available to the Academic Research access level,
+// the others are available to every level.
+var academic_operators = map[string]bool{
+	"$":              true,
+	"bio:":           true,
+	"bio_name:":      true,
+	"bio_location:":  true,
+	"place:":         true,
+	"place_country:": true,
+	"point_radius:":  true,
+	"bounding_box:":  true,
+	"has:geo":        true,
+	"has:cashtags":   true,
+	"is:nullcast":    true,
+}
+
+// Whether or not the operator is available at the access level,
+// operator is the name used by Query, like "from:", "#" or "has:geo".
+func (level AccessLevel) Allows(operator string) bool {
+	return !academic_operators[operator] || level >= Academic
+}
+
+// Checks q can be sent at the access level, its operators must be available,
+// it must have a standalone operator, and it must fit in the length limit as returned by Build.
+// Unknown operators are left to Twitter, since it adds new ones, Lint warns about them.
+func Validate(q Query, level AccessLevel) error {
+	if err := validate(q, level); err != nil {
+		return err
+	}
+	return CheckLength(q.String(), level)
+}
+
+// Parses the query and checks it like Validate, but the length is measured on s as it's written,
+// since it's what is sent, not the normalized form Validate measures, which may have more parentheses.
+func ValidateString(s string, level AccessLevel) error {
+	q, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if err := validate(q, level); err != nil {
+		return err
+	}
+	return CheckLength(s, level)
+}
+
+// Checks everything Validate does, except the length.
+func validate(q Query, level AccessLevel) error {
+	if q == nil || strings.TrimSpace(q.String()) == "" {
+		return fmt.Errorf("%w: empty query", ErrInvalid)
+	}
+
+	err := q.walk(func(q Query) error {
+		switch q := q.(type) {
+		case *term:
+			if q.operator != "" && !level.Allows(q.operator) {
+				return fmt.Errorf("%w: %s needs %s access, not %s", ErrNotAvailable, q.operator, Academic, level)
+			}
+		case *group:
+			if len(q.queries) == 0 {
+				return fmt.Errorf("%w: empty group", ErrInvalid)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !q.standalone() {
+		return fmt.Errorf("%w: %s", ErrNotStandalone, q)
+	}
+	return nil
+}
+
+// Checks the query fits in the length limit of the access level.
+func CheckLength(query string, level AccessLevel) error {
+	if length := utf8.RuneCountInString(query); length > level.MaxLength() {
+		return fmt.Errorf("%w: %d characters, %s allows %d", ErrTooLong, length, level, level.MaxLength())
+	}
+	return nil
+}
+
+// Validates q and returns it in the v2 syntax.
+func Build(q Query, level AccessLevel) (string, error) {
+	if err := Validate(q, level); err != nil {
+		return "", err
+	}
+	return q.String(), nil
+}
+
+func check_coordinates(longitude, latitude float64) error {
+	if longitude < -180 || longitude > 180 || latitude < -90 || latitude > 90 {
+		return fmt.Errorf("%w: coordinates [%v %v] are out of range", ErrInvalid, longitude, latitude)
+	}
+	return nil
+}
+
+// Radius is a number followed by "mi" or "km", up to 25 miles.
+func check_radius(radius string) error {
+	max := 0.0
+	switch {
+	case strings.HasSuffix(radius, "mi"):
+		max = 25
+	case strings.HasSuffix(radius, "km"):
+		max = 40
+	default:
+		return fmt.Errorf("%w: radius %q must end with mi or km", ErrInvalid, radius)
+	}
+
+	value, err := strconv.ParseFloat(radius[:len(radius)-2], 64)
+	if err != nil || value <= 0 || value > max {
+		return fmt.Errorf("%w: radius %q must be a positive number up to 25mi or 40km", ErrInvalid, radius)
+	}
+	return nil
+}
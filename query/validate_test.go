@@ -0,0 +1,62 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		query string
+		level AccessLevel
+		// Expected error, nil if the query is valid.
+		want error
+	}{
+		{"golang lang:en", Essential, nil},
+		{"from:twitterdev -is:retweet", Essential, nil},
+		{"in_reply_to_tweet_id:123", Essential, nil},
+		{"retweets_of_tweet_id:1 OR quotes_of_tweet_id:2", Essential, nil},
+		// Unknown operators are left to Twitter.
+		{"golang foo:bar", Essential, nil},
+		{"golang has:geo", Essential, ErrNotAvailable},
+		{"golang has:geo", Academic, nil},
+		{"bio:gopher", Elevated, ErrNotAvailable},
+		{"lang:en", Essential, ErrNotStandalone},
+		{"-golang", Essential, ErrNotStandalone},
+		{"golang OR is:retweet", Essential, ErrNotStandalone},
+		{strings.Repeat("a", 513), Elevated, ErrTooLong},
+		{strings.Repeat("a", 513), Academic, nil},
+	}
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", test.query, err)
+			continue
+		}
+		err = Validate(q, test.level)
+		if test.want == nil && err != nil {
+			t.Errorf("Validate(%q, %s) error: %v", test.query, test.level, err)
+		} else if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("Validate(%q, %s) error = %v, want %v", test.query, test.level, err, test.want)
+		}
+	}
+}
+
+func TestValidateString(t *testing.T) {
+	// Formatting adds parentheses, "a OR b c" becomes "a OR (b c)", but the query is sent as written.
+	fits := "a OR b " + strings.Repeat("c", 512-len("a OR b "))
+	if err := ValidateString(fits, Essential); err != nil {
+		t.Errorf("ValidateString of %d characters error: %v", len(fits), err)
+	}
+	if err := ValidateString(fits+"c", Essential); !errors.Is(err, ErrTooLong) {
+		t.Errorf("ValidateString of %d characters error = %v, want %v", len(fits)+1, err, ErrTooLong)
+	}
+	if err := ValidateString("lang:en", Essential); !errors.Is(err, ErrNotStandalone) {
+		t.Errorf("ValidateString(%q) error = %v, want %v", "lang:en", err, ErrNotStandalone)
+	}
+	var syntax_error *SyntaxError
+	if err := ValidateString("(a OR b", Essential); !errors.As(err, &syntax_error) {
+		t.Errorf("ValidateString(%q) error = %v, want a *SyntaxError", "(a OR b", err)
+	}
+}
@@ -0,0 +1,277 @@
+package twigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Returned by a client with the RateLimitFail policy, when a request would exceed the rate limit of its endpoint.
+var ErrWouldExceedLimit = errors.New("twigo: request would exceed the rate limit")
+
+// What to do when an endpoint has no requests left in the current window.
+type RateLimitPolicy int8
+
+const (
+	// Block until the window resets, or the context of the request is done.
+	RateLimitWait RateLimitPolicy = iota
+	// Return ErrWouldExceedLimit immediately.
+	RateLimitFail
+)
+
+// Length of a rate limit window of Twitter.
+const rate_limit_window = 15 * time.Minute
+
+// Enables the client-side rate limiter, requests are not limited by default.
+//
+// Every endpoint has its own token bucket for app-only (bearer token) and user context calls,
+// seeded from the documented limits of Twitter, and corrected from the X-Rate-Limit-* headers of every response,
+// so it's shared by all goroutines using the client.
+//
+// A bucket holds up to the limit of its endpoint, and refills at the limit per 15 minutes window,
+// so after a burst the requests are spread over the window, instead of waiting for its reset all together.
+// When Twitter reports no requests left, or answers 429, the endpoint waits for the reset of its window.
+func WithRateLimiter(policy RateLimitPolicy) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = &rate_limiter{policy: policy, buckets: map[string]*rate_bucket{}, blocked: map[string]time.Time{}}
+	}
+}
+
+// Requests an endpoint can send now, refilled continuously at limit per window.
+type rate_bucket struct {
+	limit  int
+	tokens float64
+	// When tokens were last refilled.
+	refilled time.Time
+	// No requests are sent until then, set when Twitter reports the window is used up.
+	until time.Time
+}
+
+// Adds the tokens refilled since the last time, up to the limit.
+func (b *rate_bucket) refill(now time.Time) {
+	if now.After(b.refilled) {
+		b.tokens += float64(b.limit) * float64(now.Sub(b.refilled)) / float64(rate_limit_window)
+		if b.tokens > float64(b.limit) {
+			b.tokens = float64(b.limit)
+		}
+		b.refilled = now
+	}
+}
+
+// Time until the bucket can send a request.
+func (b *rate_bucket) wait(now time.Time) time.Duration {
+	if now.Before(b.until) {
+		return b.until.Sub(now)
+	}
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) * float64(rate_limit_window) / float64(b.limit))
+}
+
+// When the bucket is full again, or when the window used up ends if it's later.
+func (b *rate_bucket) reset(now time.Time) time.Time {
+	full := now.Add(time.Duration((float64(b.limit) - b.tokens) * float64(rate_limit_window) / float64(b.limit)))
+	if b.until.After(full) {
+		return b.until
+	}
+	return full
+}
+
+type rate_limiter struct {
+	policy  RateLimitPolicy
+	mu      sync.Mutex
+	buckets map[string]*rate_bucket
+	// Endpoints with an unknown limit that answered 429, blocked until their window resets.
+	blocked map[string]time.Time
+}
+
+// Key of the bucket of a request, like "OAuth_2 GET users/:id/tweets",
+// user context requests share the same bucket, whether they use OAuth 1.0a or OAuth 2.0.
+func rate_limit_key(oauth_type OAuthType, method, route string) string {
+	if oauth_type != OAuth_2 {
+		oauth_type = OAuth_1a
+	}
+	return oauth_type.String() + " " + method + " " + route_template(method, route)
+}
+
+// Returns the bucket of key, seeding it from the known limits if it's new,
+// nil if the limit of the endpoint is not known yet. l.mu must be held.
+func (l *rate_limiter) bucket(key string, now time.Time) *rate_bucket {
+	bucket, ok := l.buckets[key]
+	if !ok {
+		limit := known_rate_limit(key)
+		if limit == 0 {
+			return nil
+		}
+		bucket = &rate_bucket{limit: limit, tokens: float64(limit), refilled: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.refill(now)
+	return bucket
+}
+
+// Takes a token from the bucket of key, waiting for one to be refilled if it's empty.
+func (l *rate_limiter) wait(ctx context.Context, key string) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		var wait time.Duration
+		if until, ok := l.blocked[key]; ok && now.Before(until) {
+			wait = until.Sub(now)
+		} else {
+			delete(l.blocked, key)
+			bucket := l.bucket(key, now)
+			if bucket == nil {
+				l.mu.Unlock()
+				return nil
+			}
+			if wait = bucket.wait(now); wait == 0 {
+				bucket.tokens--
+				l.mu.Unlock()
+				return nil
+			}
+		}
+		l.mu.Unlock()
+
+		if l.policy == RateLimitFail {
+			return fmt.Errorf("%w: %s, resets in %s", ErrWouldExceedLimit, key, wait.Round(time.Second))
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Corrects the bucket of key using the rate limit headers of the response.
+func (l *rate_limiter) update(key string, response *http.Response) {
+	limits := RateLimits{}
+	limits.Set(response.Header)
+	if limits.Limit == 0 && response.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	reset := now.Add(rate_limit_window)
+	if limits.ResetTimestamp != 0 {
+		reset = time.Unix(limits.ResetTimestamp, 0)
+	}
+
+	bucket := l.bucket(key, now)
+	if bucket == nil {
+		// A 429 doesn't tell the limit, so the endpoint is only blocked until the window resets.
+		if limits.Limit == 0 {
+			l.blocked[key] = reset
+			return
+		}
+		bucket = &rate_bucket{limit: limits.Limit, tokens: float64(limits.Limit), refilled: now}
+		l.buckets[key] = bucket
+	}
+	if limits.Limit != 0 {
+		bucket.limit = limits.Limit
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		bucket.tokens = 0
+		bucket.until = reset
+		return
+	}
+	// The bucket never holds more than Twitter has left in its window, but it only grows by refilling,
+	// so a burst at the start of a new window is spread too.
+	if remaining := float64(limits.Remaining); remaining < bucket.tokens {
+		bucket.tokens = remaining
+	}
+	if limits.Remaining == 0 {
+		bucket.until = reset
+	}
+}
+
+// Returns the state of the client-side rate limit of an endpoint,
+// ok is false if the rate limiter is not enabled, or the limit of the endpoint is not known yet.
+//
+// route is either a path like "users/2244994945/tweets", or a template like "users/:id/tweets".
+func (c *Client) RateLimitStatus(oauth_type OAuthType, method, route string) (limits RateLimits, ok bool) {
+	if c.rateLimiter == nil {
+		return RateLimits{}, false
+	}
+
+	c.rateLimiter.mu.Lock()
+	defer c.rateLimiter.mu.Unlock()
+
+	bucket := c.rateLimiter.bucket(rate_limit_key(oauth_type, method, route), time.Now())
+	if bucket == nil {
+		return RateLimits{}, false
+	}
+	now := time.Now()
+	remaining := int(bucket.tokens)
+	if now.Before(bucket.until) {
+		remaining = 0
+	}
+	return RateLimits{
+		Limit:          bucket.limit,
+		Remaining:      remaining,
+		ResetTimestamp: bucket.reset(now).Unix(),
+	}, true
+}
+
+// Returns the known limit of the endpoint of key, zero if it's not known.
+func known_rate_limit(key string) int {
+	auth, endpoint, _ := strings.Cut(key, " ")
+	method, template, _ := strings.Cut(endpoint, " ")
+	for _, endpoint := range endpoint_registry {
+		if endpoint.Method == method && endpoint.Path == template {
+			if auth == OAuth_2.String() {
+				return endpoint.AppRateLimit
+			}
+			return endpoint.UserRateLimit
+		}
+	}
+	return 0
+}
+
+// Returns the template of a route, like "users/:id/tweets" for "users/2244994945/tweets",
+// using the path of the matching endpoint of the registry.
+// Numeric segments of unknown routes are replaced by ":id".
+func route_template(method, route string) string {
+	if endpoint := match_endpoint(method, route); endpoint != nil {
+		return endpoint.Path
+	}
+
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	for i, segment := range segments {
+		if _, err := strconv.ParseUint(segment, 10, 64); err == nil {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Matches the segments of a route against a template,
+// the score is the number of literal segments, so more specific templates score higher.
+func match_template(template string, segments []string) (int, bool) {
+	template_segments := strings.Split(template, "/")
+	if len(template_segments) != len(segments) {
+		return 0, false
+	}
+
+	score := 0
+	for i, template_segment := range template_segments {
+		switch {
+		case strings.HasPrefix(template_segment, ":"):
+		case template_segment == segments[i]:
+			score++
+		default:
+			return 0, false
+		}
+	}
+	return score, true
+}
@@ -0,0 +1,310 @@
+package twigo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func new_test_rate_limiter(policy RateLimitPolicy) *rate_limiter {
+	c := &Client{}
+	WithRateLimiter(policy)(c)
+	return c.rateLimiter
+}
+
+func rate_limit_response(status, limit, remaining int, reset time.Time) *http.Response {
+	header := http.Header{}
+	if limit != 0 {
+		header.Set("X-Rate-Limit-Limit", strconv.Itoa(limit))
+		header.Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+		header.Set("X-Rate-Limit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	}
+	return &http.Response{StatusCode: status, Header: header}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	tests := []struct {
+		oauth_type OAuthType
+		method     string
+		route      string
+		want       string
+	}{
+		{OAuth_2, "GET", "users/2244994945/tweets", "OAuth_2 GET users/:id/tweets"},
+		{OAuth_1a, "DELETE", "tweets/1228393702244134912", "OAuth_1a DELETE tweets/:id"},
+		{OAuth_2_User, "DELETE", "tweets/1228393702244134912", "OAuth_1a DELETE tweets/:id"},
+		{OAuth_2, "GET", "unknown/123/route", "OAuth_2 GET unknown/:id/route"},
+	}
+	for _, test := range tests {
+		if got := rate_limit_key(test.oauth_type, test.method, test.route); got != test.want {
+			t.Errorf("rate_limit_key(%s, %s, %s) = %q, want %q", test.oauth_type, test.method, test.route, got, test.want)
+		}
+	}
+}
+
+func TestRateLimiterUpdate(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	known := rate_limit_key(OAuth_1a, "DELETE", "tweets/1")
+	unknown := rate_limit_key(OAuth_2, "GET", "unknown/1")
+
+	tests := []struct {
+		name     string
+		key      string
+		response *http.Response
+		// Expected bucket, nil if there must be none, its until is only checked if it's set.
+		want *rate_bucket
+		// Whether or not the key must be blocked without a bucket.
+		blocked bool
+	}{
+		{
+			name:     "headers of a known endpoint",
+			key:      known,
+			response: rate_limit_response(http.StatusOK, 50, 10, reset),
+			want:     &rate_bucket{limit: 50, tokens: 10},
+		},
+		{
+			name:     "headers of an unknown endpoint",
+			key:      unknown,
+			response: rate_limit_response(http.StatusOK, 20, 5, reset),
+			want:     &rate_bucket{limit: 20, tokens: 5},
+		},
+		{
+			name:     "no headers of an unknown endpoint",
+			key:      unknown,
+			response: rate_limit_response(http.StatusOK, 0, 0, reset),
+		},
+		{
+			name:     "window used up",
+			key:      known,
+			response: rate_limit_response(http.StatusOK, 50, 0, reset),
+			want:     &rate_bucket{limit: 50, tokens: 0, until: reset},
+		},
+		{
+			name:     "429 with headers",
+			key:      known,
+			response: rate_limit_response(http.StatusTooManyRequests, 50, 3, reset),
+			want:     &rate_bucket{limit: 50, tokens: 0, until: reset},
+		},
+		{
+			name:     "429 without headers of a known endpoint",
+			key:      known,
+			response: rate_limit_response(http.StatusTooManyRequests, 0, 0, reset),
+			want:     &rate_bucket{limit: 50, tokens: 0},
+		},
+		{
+			name:     "429 without headers of an unknown endpoint",
+			key:      unknown,
+			response: rate_limit_response(http.StatusTooManyRequests, 0, 0, reset),
+			blocked:  true,
+		},
+	}
+	for _, test := range tests {
+		l := new_test_rate_limiter(RateLimitWait)
+		l.update(test.key, test.response)
+
+		bucket := l.buckets[test.key]
+		switch {
+		case test.want == nil && bucket != nil:
+			t.Errorf("%s: bucket = %+v, want none", test.name, bucket)
+		case test.want != nil && bucket == nil:
+			t.Errorf("%s: no bucket, want %+v", test.name, test.want)
+		case test.want != nil && (bucket.limit != test.want.limit || int(bucket.tokens) != int(test.want.tokens)):
+			t.Errorf("%s: bucket = %+v, want %+v", test.name, bucket, test.want)
+		case test.want != nil && !test.want.until.IsZero() && !bucket.until.Equal(test.want.until):
+			t.Errorf("%s: until = %s, want %s", test.name, bucket.until, test.want.until)
+		}
+		if _, blocked := l.blocked[test.key]; blocked != test.blocked {
+			t.Errorf("%s: blocked = %t, want %t", test.name, blocked, test.blocked)
+		}
+	}
+}
+
+func TestRateLimiterUpdateOnlyRefills(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute)
+	key := rate_limit_key(OAuth_1a, "DELETE", "tweets/1")
+	l := new_test_rate_limiter(RateLimitWait)
+
+	// Responses of concurrent requests arrive out of order, the smaller remaining is kept.
+	l.update(key, rate_limit_response(http.StatusOK, 50, 10, reset))
+	l.update(key, rate_limit_response(http.StatusOK, 50, 12, reset))
+	if tokens := int(l.buckets[key].tokens); tokens != 10 {
+		t.Errorf("tokens = %d, want 10", tokens)
+	}
+
+	// A new window doesn't refill the bucket at once, so its requests are spread too.
+	l.update(key, rate_limit_response(http.StatusOK, 50, 49, reset.Add(rate_limit_window)))
+	if tokens := int(l.buckets[key].tokens); tokens != 10 {
+		t.Errorf("tokens = %d, want 10", tokens)
+	}
+}
+
+func TestRateBucketRefill(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		bucket rate_bucket
+		// Expected tokens after the refill, and wait before the next request.
+		want_tokens float64
+		want_wait   time.Duration
+	}{
+		{
+			name:        "one token per second",
+			bucket:      rate_bucket{limit: 900, tokens: 0, refilled: now.Add(-2500 * time.Millisecond)},
+			want_tokens: 2.5,
+		},
+		{
+			name:        "up to the limit",
+			bucket:      rate_bucket{limit: 900, tokens: 899, refilled: now.Add(-time.Hour)},
+			want_tokens: 900,
+		},
+		{
+			name:        "half a token",
+			bucket:      rate_bucket{limit: 900, tokens: 0.5, refilled: now},
+			want_tokens: 0.5,
+			want_wait:   500 * time.Millisecond,
+		},
+		{
+			name:        "window used up",
+			bucket:      rate_bucket{limit: 900, tokens: 5, refilled: now, until: now.Add(time.Minute)},
+			want_tokens: 5,
+			want_wait:   time.Minute,
+		},
+	}
+	for _, test := range tests {
+		bucket := test.bucket
+		bucket.refill(now)
+		if bucket.tokens != test.want_tokens {
+			t.Errorf("%s: tokens = %g, want %g", test.name, bucket.tokens, test.want_tokens)
+		}
+		if wait := bucket.wait(now); wait != test.want_wait {
+			t.Errorf("%s: wait = %s, want %s", test.name, wait, test.want_wait)
+		}
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	now := time.Now()
+	known := rate_limit_key(OAuth_1a, "DELETE", "tweets/1")
+	unknown := rate_limit_key(OAuth_2, "GET", "unknown/1")
+
+	tests := []struct {
+		name  string
+		key   string
+		setup func(l *rate_limiter)
+		// Expected error, nil if the request can be sent.
+		want error
+	}{
+		{
+			name: "known endpoint",
+			key:  known,
+		},
+		{
+			name: "unknown endpoint",
+			key:  unknown,
+		},
+		{
+			name: "empty bucket",
+			key:  known,
+			setup: func(l *rate_limiter) {
+				l.buckets[known] = &rate_bucket{limit: 50, tokens: 0, refilled: now}
+			},
+			want: ErrWouldExceedLimit,
+		},
+		{
+			name: "bucket refilled since it was empty",
+			key:  known,
+			setup: func(l *rate_limiter) {
+				l.buckets[known] = &rate_bucket{limit: 50, tokens: 0, refilled: now.Add(-time.Minute)}
+			},
+		},
+		{
+			name: "window used up",
+			key:  known,
+			setup: func(l *rate_limiter) {
+				l.buckets[known] = &rate_bucket{limit: 50, tokens: 10, refilled: now, until: now.Add(time.Minute)}
+			},
+			want: ErrWouldExceedLimit,
+		},
+		{
+			name: "used up window of the past",
+			key:  known,
+			setup: func(l *rate_limiter) {
+				l.buckets[known] = &rate_bucket{limit: 50, tokens: 10, refilled: now, until: now.Add(-time.Second)}
+			},
+		},
+		{
+			name: "blocked endpoint",
+			key:  unknown,
+			setup: func(l *rate_limiter) {
+				l.blocked[unknown] = now.Add(time.Minute)
+			},
+			want: ErrWouldExceedLimit,
+		},
+		{
+			name: "block of a past window",
+			key:  unknown,
+			setup: func(l *rate_limiter) {
+				l.blocked[unknown] = now.Add(-time.Second)
+			},
+		},
+	}
+	for _, test := range tests {
+		l := new_test_rate_limiter(RateLimitFail)
+		if test.setup != nil {
+			test.setup(l)
+		}
+
+		err := l.wait(context.Background(), test.key)
+		if test.want == nil && err != nil {
+			t.Errorf("%s: error: %v", test.name, err)
+		} else if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("%s: error = %v, want %v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestRateLimiterWaitTakesTokens(t *testing.T) {
+	key := rate_limit_key(OAuth_1a, "DELETE", "tweets/1")
+	l := new_test_rate_limiter(RateLimitFail)
+	l.buckets[key] = &rate_bucket{limit: 50, tokens: 2, refilled: time.Now()}
+
+	for i := 0; i < 2; i++ {
+		if err := l.wait(context.Background(), key); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if err := l.wait(context.Background(), key); !errors.Is(err, ErrWouldExceedLimit) {
+		t.Errorf("third request error = %v, want %v", err, ErrWouldExceedLimit)
+	}
+}
+
+func TestRateLimiterWaitSpreadsRequests(t *testing.T) {
+	key := rate_limit_key(OAuth_1a, "DELETE", "tweets/1")
+	l := new_test_rate_limiter(RateLimitWait)
+	// A token every 20 milliseconds.
+	l.buckets[key] = &rate_bucket{limit: int(rate_limit_window / (20 * time.Millisecond)), tokens: 0, refilled: time.Now()}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background(), key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("3 requests took %s, want about 60ms", elapsed)
+	}
+}
+
+func TestRateLimiterWaitContext(t *testing.T) {
+	key := rate_limit_key(OAuth_1a, "DELETE", "tweets/1")
+	l := new_test_rate_limiter(RateLimitWait)
+	l.buckets[key] = &rate_bucket{limit: 50, tokens: 0, refilled: time.Now()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, key); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
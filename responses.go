@@ -1,6 +1,7 @@
 package twigo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,11 +10,6 @@ import (
 	"github.com/arshamalh/twigo/entities"
 )
 
-type CallerData struct {
-	ID     string
-	Params Map
-}
-
 // *** Response Entities *** //
 type TweetResponse struct {
 	Data       entities.Tweet
@@ -36,8 +32,8 @@ type TweetsResponse struct {
 	Errors     []ErrorEntity
 	Meta       MetaEntity
 	RateLimits RateLimits
-	CallerData CallerData
-	Caller     func(string, Map) (*TweetsResponse, error)
+	ctx        context.Context
+	fetch      page_fetcher[TweetsResponse]
 }
 
 func (r *TweetsResponse) Parse(raw_response *http.Response) (*TweetsResponse, error) {
@@ -47,12 +43,20 @@ func (r *TweetsResponse) Parse(raw_response *http.Response) (*TweetsResponse, er
 	return r, err
 }
 
+// Returns the next page, using the context of the call that returned r.
 func (r *TweetsResponse) NextPage() (*TweetsResponse, error) {
-	if r.Meta.NextToken == "" {
+	if r.ctx == nil {
+		return r.NextPageCtx(context.Background())
+	}
+	return r.NextPageCtx(r.ctx)
+}
+
+// Same as NextPage, but the request is bound to ctx.
+func (r *TweetsResponse) NextPageCtx(ctx context.Context) (*TweetsResponse, error) {
+	if r.Meta.NextToken == "" || r.fetch == nil {
 		return nil, fmt.Errorf("no next page")
 	}
-	r.CallerData.Params["pagination_token"] = r.Meta.NextToken
-	return r.Caller(r.CallerData.ID, r.CallerData.Params)
+	return r.fetch(ctx, r.Meta.NextToken)
 }
 
 type BookmarkedTweetsResponse struct {
@@ -61,8 +65,8 @@ type BookmarkedTweetsResponse struct {
 	Errors     []ErrorEntity
 	Meta       MetaEntity
 	RateLimits RateLimits
-	CallerData CallerData
-	Caller     func(Map) (*BookmarkedTweetsResponse, error)
+	ctx        context.Context
+	fetch      page_fetcher[BookmarkedTweetsResponse]
 }
 
 func (r *BookmarkedTweetsResponse) Parse(raw_response *http.Response) (*BookmarkedTweetsResponse, error) {
@@ -72,12 +76,20 @@ func (r *BookmarkedTweetsResponse) Parse(raw_response *http.Response) (*Bookmark
 	return r, err
 }
 
+// Returns the next page, using the context of the call that returned r.
 func (r *BookmarkedTweetsResponse) NextPage() (*BookmarkedTweetsResponse, error) {
-	if r.Meta.NextToken == "" {
+	if r.ctx == nil {
+		return r.NextPageCtx(context.Background())
+	}
+	return r.NextPageCtx(r.ctx)
+}
+
+// Same as NextPage, but the request is bound to ctx.
+func (r *BookmarkedTweetsResponse) NextPageCtx(ctx context.Context) (*BookmarkedTweetsResponse, error) {
+	if r.Meta.NextToken == "" || r.fetch == nil {
 		return nil, fmt.Errorf("no next page")
 	}
-	r.CallerData.Params["pagination_token"] = r.Meta.NextToken
-	return r.Caller(r.CallerData.Params)
+	return r.fetch(ctx, r.Meta.NextToken)
 }
 
 type UserResponse struct {
@@ -101,8 +113,8 @@ type UsersResponse struct {
 	Errors     []ErrorEntity
 	Meta       MetaEntity
 	RateLimits RateLimits
-	CallerData CallerData
-	Caller     func(string, Map) (*UsersResponse, error)
+	ctx        context.Context
+	fetch      page_fetcher[UsersResponse]
 }
 
 func (r *UsersResponse) Parse(raw_response *http.Response) (*UsersResponse, error) {
@@ -112,12 +124,20 @@ func (r *UsersResponse) Parse(raw_response *http.Response) (*UsersResponse, erro
 	return r, err
 }
 
+// Returns the next page, using the context of the call that returned r.
 func (r *UsersResponse) NextPage() (*UsersResponse, error) {
-	if r.Meta.NextToken == "" {
+	if r.ctx == nil {
+		return r.NextPageCtx(context.Background())
+	}
+	return r.NextPageCtx(r.ctx)
+}
+
+// Same as NextPage, but the request is bound to ctx.
+func (r *UsersResponse) NextPageCtx(ctx context.Context) (*UsersResponse, error) {
+	if r.Meta.NextToken == "" || r.fetch == nil {
 		return nil, fmt.Errorf("no next page")
 	}
-	r.CallerData.Params["pagination_token"] = r.Meta.NextToken
-	return r.Caller(r.CallerData.ID, r.CallerData.Params)
+	return r.fetch(ctx, r.Meta.NextToken)
 }
 
 type MutedUsersResponse struct {
@@ -126,8 +146,8 @@ type MutedUsersResponse struct {
 	Errors     []ErrorEntity
 	Meta       MetaEntity
 	RateLimits RateLimits
-	CallerData CallerData
-	Caller     func(Map) (*MutedUsersResponse, error)
+	ctx        context.Context
+	fetch      page_fetcher[MutedUsersResponse]
 }
 
 func (r *MutedUsersResponse) Parse(raw_response *http.Response) (*MutedUsersResponse, error) {
@@ -137,12 +157,20 @@ func (r *MutedUsersResponse) Parse(raw_response *http.Response) (*MutedUsersResp
 	return r, err
 }
 
+// Returns the next page, using the context of the call that returned r.
 func (r *MutedUsersResponse) NextPage() (*MutedUsersResponse, error) {
-	if r.Meta.NextToken == "" {
+	if r.ctx == nil {
+		return r.NextPageCtx(context.Background())
+	}
+	return r.NextPageCtx(r.ctx)
+}
+
+// Same as NextPage, but the request is bound to ctx.
+func (r *MutedUsersResponse) NextPageCtx(ctx context.Context) (*MutedUsersResponse, error) {
+	if r.Meta.NextToken == "" || r.fetch == nil {
 		return nil, fmt.Errorf("no next page")
 	}
-	r.CallerData.Params["pagination_token"] = r.Meta.NextToken
-	return r.Caller(r.CallerData.Params)
+	return r.fetch(ctx, r.Meta.NextToken)
 }
 
 type SpaceResponse struct {
@@ -196,8 +224,8 @@ type ListsResponse struct {
 	Errors     []ErrorEntity
 	Meta       MetaEntity
 	RateLimits RateLimits
-	CallerData CallerData
-	Caller     func(string, Map) (*ListsResponse, error)
+	ctx        context.Context
+	fetch      page_fetcher[ListsResponse]
 }
 
 func (r *ListsResponse) Parse(raw_response *http.Response) (*ListsResponse, error) {
@@ -207,12 +235,20 @@ func (r *ListsResponse) Parse(raw_response *http.Response) (*ListsResponse, erro
 	return r, err
 }
 
+// Returns the next page, using the context of the call that returned r.
 func (r *ListsResponse) NextPage() (*ListsResponse, error) {
-	if r.Meta.NextToken == "" {
+	if r.ctx == nil {
+		return r.NextPageCtx(context.Background())
+	}
+	return r.NextPageCtx(r.ctx)
+}
+
+// Same as NextPage, but the request is bound to ctx.
+func (r *ListsResponse) NextPageCtx(ctx context.Context) (*ListsResponse, error) {
+	if r.Meta.NextToken == "" || r.fetch == nil {
 		return nil, fmt.Errorf("no next page")
 	}
-	r.CallerData.Params["pagination_token"] = r.Meta.NextToken
-	return r.Caller(r.CallerData.ID, r.CallerData.Params)
+	return r.fetch(ctx, r.Meta.NextToken)
 }
 
 type LikeResponse struct {
@@ -403,19 +439,16 @@ func (r *UpdateListResponse) Parse(raw_response *http.Response) (*UpdateListResp
 }
 
 type TweetsCountResponse struct {
-	Data struct {
-		End        time.Time `json:"end"`
-		Start      time.Time `json:"start"`
-		TweetCount int       `json:"tweet_count"`
-	}
+	Data CountSeries
 	Meta struct {
-		// TODO: Also there is a "meta" field in the response that is a object
 		TotalTweetCount int    `json:"total_tweet_count"`
 		NextToken       string `json:"next_token"`
 	}
 	Includes   IncludesEntity
 	Errors     []ErrorEntity
 	RateLimits RateLimits
+	ctx        context.Context
+	fetch      page_fetcher[TweetsCountResponse]
 }
 
 func (r *TweetsCountResponse) Parse(raw_response *http.Response) (*TweetsCountResponse, error) {
@@ -425,6 +458,22 @@ func (r *TweetsCountResponse) Parse(raw_response *http.Response) (*TweetsCountRe
 	return r, err
 }
 
+// Returns the next page, using the context of the call that returned r.
+func (r *TweetsCountResponse) NextPage() (*TweetsCountResponse, error) {
+	if r.ctx == nil {
+		return r.NextPageCtx(context.Background())
+	}
+	return r.NextPageCtx(r.ctx)
+}
+
+// Same as NextPage, but the request is bound to ctx.
+func (r *TweetsCountResponse) NextPageCtx(ctx context.Context) (*TweetsCountResponse, error) {
+	if r.Meta.NextToken == "" || r.fetch == nil {
+		return nil, fmt.Errorf("no next page")
+	}
+	return r.fetch(ctx, r.Meta.NextToken)
+}
+
 type ComplianceJobResponse struct {
 	Data       entities.ComplianceJob
 	Includes   IncludesEntity
@@ -454,3 +503,79 @@ func (r *ComplianceJobsResponse) Parse(raw_response *http.Response) (*Compliance
 	r.RateLimits.Set(raw_response.Header)
 	return r, err
 }
+
+type StreamRulesResponse struct {
+	Data []entities.StreamRule
+	Meta struct {
+		Sent        time.Time `json:"sent"`
+		ResultCount int       `json:"result_count"`
+		Summary     struct {
+			Created    int `json:"created"`
+			NotCreated int `json:"not_created"`
+			Valid      int `json:"valid"`
+			Invalid    int `json:"invalid"`
+			Deleted    int `json:"deleted"`
+			NotDeleted int `json:"not_deleted"`
+		} `json:"summary"`
+	}
+	Errors     []entities.PartialError
+	RateLimits RateLimits
+}
+
+func (r *StreamRulesResponse) Parse(raw_response *http.Response) (*StreamRulesResponse, error) {
+	err := json.NewDecoder(raw_response.Body).Decode(&r)
+	defer raw_response.Body.Close()
+	r.RateLimits.Set(raw_response.Header)
+	return r, err
+}
+
+type DMEventsResponse struct {
+	Data       []entities.DMEvent
+	Includes   IncludesEntity
+	Errors     []ErrorEntity
+	Meta       MetaEntity
+	RateLimits RateLimits
+	ctx        context.Context
+	fetch      page_fetcher[DMEventsResponse]
+}
+
+func (r *DMEventsResponse) Parse(raw_response *http.Response) (*DMEventsResponse, error) {
+	err := json.NewDecoder(raw_response.Body).Decode(&r)
+	defer raw_response.Body.Close()
+	r.RateLimits.Set(raw_response.Header)
+	return r, err
+}
+
+// Returns the next page, using the context of the call that returned r.
+func (r *DMEventsResponse) NextPage() (*DMEventsResponse, error) {
+	if r.ctx == nil {
+		return r.NextPageCtx(context.Background())
+	}
+	return r.NextPageCtx(r.ctx)
+}
+
+// Same as NextPage, but the request is bound to ctx.
+func (r *DMEventsResponse) NextPageCtx(ctx context.Context) (*DMEventsResponse, error) {
+	if r.Meta.NextToken == "" || r.fetch == nil {
+		return nil, fmt.Errorf("no next page")
+	}
+	return r.fetch(ctx, r.Meta.NextToken)
+}
+
+type SendDMResponse struct {
+	Data struct {
+		DMConversationID string `json:"dm_conversation_id"`
+		DMEventID        string `json:"dm_event_id"`
+	}
+	Includes   IncludesEntity
+	Errors     []ErrorEntity
+	Meta       MetaEntity
+	RateLimits RateLimits
+}
+
+func (r *SendDMResponse) Parse(raw_response *http.Response) (*SendDMResponse, error) {
+	err := json.NewDecoder(raw_response.Body).Decode(&r)
+	defer raw_response.Body.Close()
+	r.RateLimits.Set(raw_response.Header)
+	return r, err
+}
@@ -0,0 +1,207 @@
+package twigo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy tells the client when and how many times a failed request should be sent again.
+//
+// Rate limited requests (429) wait until the reset time of the rate limit,
+// 5XX responses, retryable error codes and network errors wait using jittered exponential backoff.
+//
+// The first attempt of a request failing with a 5XX or a network error may have succeeded anyway,
+// so they are only retried for idempotent methods, like GET and DELETE, unless RetryNonIdempotent is set,
+// otherwise a retried CreateTweet could post the Tweet twice.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff of the first retry, it's doubled for each of the next retries.
+	BaseBackoff time.Duration
+	// Backoff never gets bigger than this.
+	MaxBackoff time.Duration
+	// Maximum total time to wait between attempts of a single call,
+	// the last error is returned if the next wait doesn't fit in it, zero means no limit.
+	Budget time.Duration
+	// Retries POST requests on 5XX responses and network errors too, at the risk of duplicates.
+	RetryNonIdempotent bool
+}
+
+// Methods whose requests have the same effect if they are sent twice.
+var idempotent_methods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"PUT":     true,
+	"DELETE":  true,
+}
+
+// A reasonable policy for background jobs,
+// 5 attempts, backoff from 1 second up to 1 minute, and at most 16 minutes of waiting,
+// which is enough for one 15 minutes rate limit window.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  time.Minute,
+		Budget:      16 * time.Minute,
+	}
+}
+
+// Enables retrying failed requests with the given policy,
+// requests are not retried by default.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// Returns how long to wait before the next attempt of a request with the given method,
+// and whether or not to retry at all.
+func (p *RetryPolicy) wait(err error, attempt int, method string) (time.Duration, bool) {
+	// Waiting is the job of the rate limiter, which is told not to.
+	if errors.Is(err, ErrWouldExceedLimit) {
+		return 0, false
+	}
+
+	var api_error *APIError
+	if errors.As(err, &api_error) && api_error.StatusCode == http.StatusTooManyRequests {
+		// Rate limited requests are rejected before doing anything, so they are always safe to retry.
+		if reset := api_error.RateLimits.ResetTimestamp; reset != 0 {
+			return time.Until(time.Unix(reset, 0)) + time.Second, true
+		}
+		return p.backoff(attempt), true
+	}
+	if !idempotent_methods[method] && !p.RetryNonIdempotent {
+		return 0, false
+	}
+
+	if errors.As(err, &api_error) {
+		if api_error.StatusCode >= 500 {
+			return p.backoff(attempt), true
+		}
+		for _, code := range api_error.Codes() {
+			if code.Retryable() {
+				return p.backoff(attempt), true
+			}
+		}
+		return 0, false
+	}
+
+	// Anything else is a network error.
+	return p.backoff(attempt), true
+}
+
+// Exponential backoff with jitter, attempt starts from 1.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.BaseBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// Sends the request with the given http client,
+// checks the response and retries it according to the client's retry policy.
+func (c *Client) do(http_client *http.Client, request *http.Request) (*http.Response, error) {
+	response, err := c.send(http_client, request)
+	if c.retryPolicy == nil || err == nil {
+		return response, err
+	}
+
+	waited := time.Duration(0)
+	for attempt := 1; attempt < c.retryPolicy.MaxAttempts; attempt++ {
+		// Nothing to retry if the caller gave up.
+		if request.Context().Err() != nil {
+			return nil, err
+		}
+
+		wait, ok := c.retryPolicy.wait(err, attempt, request.Method)
+		if !ok {
+			return nil, err
+		}
+		if wait < 0 {
+			wait = 0
+		}
+		if c.retryPolicy.Budget > 0 && waited+wait > c.retryPolicy.Budget {
+			return nil, err
+		}
+		waited += wait
+
+		if err := sleep(request.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		retry_request := request.Clone(request.Context())
+		if request.GetBody != nil {
+			if retry_request.Body, err = request.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		response, err = c.send(http_client, retry_request)
+		if err == nil {
+			return response, nil
+		}
+	}
+
+	return nil, err
+}
+
+// Sends a single request under the rate limiter of the client, if any, and checks its status code.
+func (c *Client) send(http_client *http.Client, request *http.Request) (*http.Response, error) {
+	key := ""
+	if c.rateLimiter != nil {
+		key = rate_limit_key(request_oauth_type(request), request.Method, c.route_of(request))
+		if err := c.rateLimiter.wait(request.Context(), key); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := http_client.Do(request)
+	if err == nil {
+		if c.rateLimiter != nil {
+			c.rateLimiter.update(key, response)
+		}
+		response, err = check_response(response)
+	}
+
+	// Requests given up by their caller say nothing about the client.
+	if c.observer != nil && request.Context().Err() == nil {
+		c.observer(err)
+	}
+	return response, err
+}
+
+// Sends a single request and checks its status code, without retrying or rate limiting it,
+// streams use it since they have their own reconnection policy.
+func send(http_client *http.Client, request *http.Request) (*http.Response, error) {
+	response, err := http_client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return check_response(response)
+}
+
+// Waits for the given duration, or returns early if ctx is done.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
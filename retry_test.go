@@ -0,0 +1,115 @@
+package twigo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A server answering with the given status codes, then with 2XX responses.
+type fake_flaky_server struct {
+	mu       sync.Mutex
+	statuses []int
+	requests int
+}
+
+func (f *fake_flaky_server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	if len(f.statuses) != 0 {
+		status := f.statuses[0]
+		f.statuses = f.statuses[1:]
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(-10*time.Second).Unix(), 10))
+		}
+		w.WriteHeader(status)
+		return
+	}
+	if r.Method == "POST" {
+		w.WriteHeader(http.StatusCreated)
+	}
+	w.Write([]byte(`{"data":{"id":"1","text":"hi"}}`))
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	non_idempotent_policy := policy
+	non_idempotent_policy.RetryNonIdempotent = true
+	budget_policy := policy
+	budget_policy.BaseBackoff = time.Hour
+	budget_policy.MaxBackoff = time.Hour
+	budget_policy.Budget = time.Second
+
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		post     bool
+		statuses []int
+		// Expected number of requests, and error, nil if the call succeeds.
+		want_requests int
+		want          error
+	}{
+		{"server error", policy, false, []int{503}, 2, nil},
+		{"too many server errors", policy, false, []int{503, 500, 502}, 3, ErrServerError},
+		{"client error", policy, false, []int{404}, 1, ErrNotFound},
+		{"rate limited", policy, false, []int{429}, 2, nil},
+		{"server error of a POST", policy, true, []int{503}, 1, ErrServerError},
+		{"server error of a POST with RetryNonIdempotent", non_idempotent_policy, true, []int{503}, 2, nil},
+		{"rate limited POST", policy, true, []int{429}, 2, nil},
+		{"backoff out of the budget", budget_policy, false, []int{503}, 1, ErrServerError},
+	}
+	for _, test := range tests {
+		flaky := &fake_flaky_server{statuses: test.statuses}
+		server := httptest.NewServer(flaky)
+		client, _ := NewClient(
+			&Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret", BearerToken: "bearer"},
+			WithBaseURL(server.URL), WithRetry(test.policy),
+		)
+
+		var err error
+		if test.post {
+			_, err = client.CreateTweet("hi", nil)
+		} else {
+			_, err = client.GetTweet("1", nil)
+		}
+		server.Close()
+
+		if test.want == nil && err != nil {
+			t.Errorf("%s: error: %v", test.name, err)
+		} else if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("%s: error = %v, want %v", test.name, err, test.want)
+		}
+		if flaky.requests != test.want_requests {
+			t.Errorf("%s: %d requests, want %d", test.name, flaky.requests, test.want_requests)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	tests := []struct {
+		attempt int
+		// Backoff without jitter, the jittered one is between its half and itself.
+		want time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, test := range tests {
+		for i := 0; i < 20; i++ {
+			if backoff := policy.backoff(test.attempt); backoff < test.want/2 || backoff > test.want {
+				t.Errorf("backoff of attempt %d = %s, want between %s and %s", test.attempt, backoff, test.want/2, test.want)
+				break
+			}
+		}
+	}
+}
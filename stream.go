@@ -0,0 +1,444 @@
+package twigo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arshamalh/twigo/entities"
+	"github.com/arshamalh/twigo/utils"
+)
+
+var (
+	// Nothing, not even a keep-alive signal, was received for Stream.KeepAliveTimeout.
+	ErrStreamStalled = errors.New("twigo: stream stalled")
+	// Twitter closed the stream, an operational disconnect for example.
+	ErrStreamDisconnected = errors.New("twigo: stream disconnected")
+)
+
+// A Tweet received from a stream.
+type StreamTweet struct {
+	Data          entities.Tweet
+	Includes      IncludesEntity
+	MatchingRules []entities.MatchingRule `json:"matching_rules"`
+	Errors        []entities.PartialError
+}
+
+// Stream keeps a streaming connection open and reconnects whenever it drops,
+// using the backoff tiers suggested by Twitter:
+// linear from 250ms up to 16s for network errors and stalls,
+// exponential from 5s up to 320s for HTTP errors,
+// and exponential from 1 minute for rate limits.
+//
+// Set the exported fields before calling Run.
+type Stream struct {
+	// Params of the stream endpoint, like "expansions", "tweet.fields" or "backfill_minutes".
+	Params Map
+	// Reconnects if nothing is received for this long, default is 30 seconds,
+	// Twitter sends a keep-alive signal every 20 seconds.
+	KeepAliveTimeout time.Duration
+	// Gives up after this many failed reconnections in a row, zero means never.
+	MaxReconnects int
+	// Called with the reason whenever the connection drops, before reconnecting.
+	OnDisconnect func(err error)
+	// Asks for the Tweets missed while disconnected when reconnecting, up to 5 minutes,
+	// the endpoint must support "backfill_minutes", and some Tweets may be delivered twice.
+	BackfillOnReconnect bool
+
+	client   *Client
+	endpoint *Endpoint
+
+	mu              sync.Mutex
+	stats           StreamStats
+	started_at      time.Time
+	disconnected_at time.Time
+	err             error
+}
+
+// Connection statistics of a Stream.
+type StreamStats struct {
+	// Number of received Tweets since Run was called.
+	Tweets int
+	// Average number of received Tweets per second since Run was called.
+	TweetsPerSecond float64
+	// Number of established connections, including reconnections.
+	Connections int
+	// Number of disconnections, grouped by their reason.
+	Disconnects map[string]int
+	// The reason of the last disconnection.
+	LastDisconnect error
+	// When the last keep-alive signal was received.
+	LastKeepAlive time.Time
+	// When the last Tweet was received.
+	LastTweet time.Time
+	// When the current connection was established, zero if it's not connected.
+	ConnectedAt time.Time
+}
+
+// Returns the filtered stream, Tweets matching the active rules are delivered to it,
+// manage the rules using AddStreamRules and DeleteStreamRules.
+//
+// Parameters
+//
+// params (keys):
+//
+//	"backfill_minutes", "expansions", "media.fields",
+//	"place.fields", "poll.fields", "tweet.fields", "user.fields"
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream
+func (c *Client) FilteredStream(params Map) *Stream {
+	return &Stream{
+		Params:   params,
+		client:   c,
+		endpoint: find_endpoint("FilteredStream"),
+	}
+}
+
+// Returns the volume stream, a random sample of about 1% of all public Tweets.
+//
+// Parameters
+//
+// params (keys):
+//
+//	"backfill_minutes", "expansions", "media.fields",
+//	"place.fields", "poll.fields", "tweet.fields", "user.fields"
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/tweets/volume-streams/api-reference/get-tweets-sample-stream
+func (c *Client) SampleStream(params Map) *Stream {
+	return &Stream{
+		Params:   params,
+		client:   c,
+		endpoint: find_endpoint("SampleStream"),
+	}
+}
+
+// Returns a partition of the 10% volume stream, which is split into partitions,
+// each one needs its own connection, so run a stream for every partition you need.
+//
+// It's only available to some access levels.
+//
+// Parameters
+//
+// partition: The partition number, starting from 1.
+//
+// params (keys):
+//
+//	"backfill_minutes", "expansions", "media.fields",
+//	"place.fields", "poll.fields", "tweet.fields", "user.fields",
+//	"start_time", "end_time"
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/enterprise/decahose-api/api-reference/decahose
+func (c *Client) Sample10Stream(partition int, params Map) *Stream {
+	stream_params := make(Map, len(params)+1)
+	for key, value := range params {
+		stream_params[key] = value
+	}
+	stream_params["partition"] = partition
+
+	return &Stream{
+		Params:   stream_params,
+		client:   c,
+		endpoint: find_endpoint("Sample10Stream"),
+	}
+}
+
+// Connects to the stream and calls handler for every received Tweet, until ctx is done.
+//
+// It returns nil when ctx is done, or the error that made it give up,
+// like an *APIError with status 401 or 403.
+func (s *Stream) Run(ctx context.Context, handler func(tweet *StreamTweet)) error {
+	// Invalid params and missing credentials fail on every connection, so there is no point in reconnecting.
+	if _, _, err := s.endpoint.split_params(s.Params); err != nil {
+		return err
+	}
+	if _, err := utils.QueryBuilder(s.Params, s.endpoint.Query); err != nil {
+		return invalid_params("%v", err)
+	}
+	if _, err := s.client.auth_for(ctx, s.endpoint); err != nil {
+		return err
+	}
+
+	backoff := stream_backoff{}
+	reconnects := 0
+
+	s.mu.Lock()
+	s.started_at = time.Now()
+	s.stats = StreamStats{Disconnects: map[string]int{}}
+	s.disconnected_at = time.Time{}
+	s.mu.Unlock()
+
+	for {
+		connected, err := s.connect(ctx, handler)
+		if ctx.Err() != nil {
+			s.mu.Lock()
+			s.stats.ConnectedAt = time.Time{}
+			s.mu.Unlock()
+			return nil
+		}
+		if connected {
+			backoff = stream_backoff{}
+			reconnects = 0
+		}
+
+		s.mu.Lock()
+		s.stats.Disconnects[err.Error()]++
+		s.stats.LastDisconnect = err
+		s.stats.ConnectedAt = time.Time{}
+		if connected {
+			s.disconnected_at = time.Now()
+		}
+		s.mu.Unlock()
+
+		if s.OnDisconnect != nil {
+			s.OnDisconnect(err)
+		}
+
+		wait, ok := backoff.next(err)
+		if !ok {
+			return err
+		}
+		reconnects++
+		if s.MaxReconnects > 0 && reconnects > s.MaxReconnects {
+			return err
+		}
+		if sleep(ctx, wait) != nil {
+			return nil
+		}
+	}
+}
+
+// Opens a single connection and reads it until it drops,
+// connected reports whether or not the connection was established at all.
+func (s *Stream) connect(ctx context.Context, handler func(tweet *StreamTweet)) (connected bool, err error) {
+	connection_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	raw_query, err := utils.QueryBuilder(s.connection_params(), s.endpoint.Query)
+	if err != nil {
+		return false, err
+	}
+	full_route := s.client.baseURL + v2_prefix + s.endpoint.Path
+	if raw_query != "" {
+		full_route += "?" + raw_query
+	}
+
+	oauth_type, err := s.client.auth_for(ctx, s.endpoint)
+	if err != nil {
+		return false, err
+	}
+	request, err := s.client.new_request(connection_ctx, s.endpoint.Method, full_route, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// The connection is meant to stay open, so the client timeout must not apply.
+	authorized_client, err := s.client.authorize(request, oauth_type)
+	if err != nil {
+		return false, err
+	}
+	http_client := *authorized_client
+	http_client.Timeout = 0
+
+	response, err := send(&http_client, request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	s.mu.Lock()
+	s.stats.Connections++
+	s.stats.ConnectedAt = time.Now()
+	s.mu.Unlock()
+
+	lines := make(chan []byte)
+	read_errors := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(response.Body)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				read_errors <- err
+				return
+			}
+			select {
+			case lines <- line:
+			case <-connection_ctx.Done():
+				return
+			}
+		}
+	}()
+
+	keep_alive_timeout := s.KeepAliveTimeout
+	if keep_alive_timeout <= 0 {
+		keep_alive_timeout = 30 * time.Second
+	}
+	timer := time.NewTimer(keep_alive_timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case <-timer.C:
+			return true, ErrStreamStalled
+		case err := <-read_errors:
+			if err == io.EOF {
+				err = fmt.Errorf("%w: connection closed", ErrStreamDisconnected)
+			}
+			return true, err
+		case line := <-lines:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(keep_alive_timeout)
+
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				// Keep-alive signal
+				s.mu.Lock()
+				s.stats.LastKeepAlive = time.Now()
+				s.mu.Unlock()
+				continue
+			}
+
+			tweet := &StreamTweet{}
+			if err := json.Unmarshal(line, tweet); err != nil {
+				return true, err
+			}
+			if tweet.Data.ID == "" && len(tweet.Errors) != 0 {
+				return true, stream_disconnect_error(tweet.Errors)
+			}
+
+			s.mu.Lock()
+			s.stats.Tweets++
+			s.stats.LastTweet = time.Now()
+			s.mu.Unlock()
+
+			handler(tweet)
+		}
+	}
+}
+
+// Returns the params of the next connection, adding "backfill_minutes" if needed.
+func (s *Stream) connection_params() Map {
+	s.mu.Lock()
+	disconnected_at := s.disconnected_at
+	s.mu.Unlock()
+
+	if !s.BackfillOnReconnect || disconnected_at.IsZero() {
+		return s.Params
+	}
+
+	params := make(Map, len(s.Params)+1)
+	for key, value := range s.Params {
+		params[key] = value
+	}
+	params["backfill_minutes"] = int(math.Min(5, math.Ceil(time.Since(disconnected_at).Minutes())))
+	return params
+}
+
+// Runs the stream in a new goroutine and delivers the Tweets over the returned channel,
+// the channel is closed when the stream stops, then Err reports why.
+func (s *Stream) Tweets(ctx context.Context) <-chan *StreamTweet {
+	tweets := make(chan *StreamTweet)
+	go func() {
+		defer close(tweets)
+		err := s.Run(ctx, func(tweet *StreamTweet) {
+			select {
+			case tweets <- tweet:
+			case <-ctx.Done():
+			}
+		})
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+	}()
+	return tweets
+}
+
+// Returns the error that stopped the stream started by Tweets, nil if it's stopped by its context.
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Returns a snapshot of the connection statistics, it's safe to call it while the stream is running.
+func (s *Stream) Stats() StreamStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.Disconnects = make(map[string]int, len(s.stats.Disconnects))
+	for reason, count := range s.stats.Disconnects {
+		stats.Disconnects[reason] = count
+	}
+	if elapsed := time.Since(s.started_at).Seconds(); !s.started_at.IsZero() && elapsed > 0 {
+		stats.TweetsPerSecond = float64(stats.Tweets) / elapsed
+	}
+	return stats
+}
+
+// Makes an error out of the errors Twitter sends before closing a stream.
+func stream_disconnect_error(stream_errors []entities.PartialError) error {
+	reason := "unknown reason"
+	if title := stream_errors[0].Title; title != nil {
+		reason = *title
+	}
+	if detail := stream_errors[0].Detail; detail != nil {
+		reason += ", " + *detail
+	}
+	return fmt.Errorf("%w: %s", ErrStreamDisconnected, reason)
+}
+
+// Keeps track of the reconnection backoffs, its zero value is ready to use.
+type stream_backoff struct {
+	network    time.Duration
+	http       time.Duration
+	rate_limit time.Duration
+}
+
+// Returns how long to wait before reconnecting after err, and whether or not to reconnect at all.
+func (b *stream_backoff) next(err error) (time.Duration, bool) {
+	var api_error *APIError
+	if !errors.As(err, &api_error) {
+		b.network += 250 * time.Millisecond
+		if b.network > 16*time.Second {
+			b.network = 16 * time.Second
+		}
+		return b.network, true
+	}
+
+	switch {
+	case api_error.StatusCode == http.StatusTooManyRequests:
+		if b.rate_limit == 0 {
+			b.rate_limit = time.Minute
+		} else {
+			b.rate_limit *= 2
+		}
+		return b.rate_limit, true
+	case api_error.StatusCode >= 500:
+		if b.http == 0 {
+			b.http = 5 * time.Second
+		} else if b.http < 320*time.Second {
+			b.http *= 2
+		}
+		return b.http, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,286 @@
+package twigo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// ** Filtered stream rules ** //
+
+// Sends a request to the stream rules endpoint named name, using the bearer token,
+// dry_run only validates the request without changing the rules.
+func (c *Client) stream_rules_request(ctx context.Context, name string, body Map, dry_run bool) (*StreamRulesResponse, error) {
+	if dry_run {
+		body["dry_run"] = true
+	}
+
+	response, err := c.call(ctx, name, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&StreamRulesResponse{}).Parse(response)
+}
+
+// Returns the rules that are currently active on the filtered stream.
+//
+// Parameters
+//
+// ids: IDs of the rules to return, pass nil to get all of them.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream-rules
+func (c *Client) GetStreamRules(ids []string) (*StreamRulesResponse, error) {
+	return c.GetStreamRulesCtx(context.Background(), ids)
+}
+
+// Same as GetStreamRules, but the request is bound to ctx.
+func (c *Client) GetStreamRulesCtx(ctx context.Context, ids []string) (*StreamRulesResponse, error) {
+	params := Map{}
+	if len(ids) != 0 {
+		params["ids"] = ids
+	}
+
+	response, err := c.call(ctx, "GetStreamRules", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&StreamRulesResponse{}).Parse(response)
+}
+
+// Adds rules to the filtered stream, each rule can have a tag to recognize the matched Tweets.
+//
+// Parameters
+//
+// rules: Rules to be added, the ID field is ignored.
+//
+// dry_run: Only validates the rules, nothing will be added.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/post-tweets-search-stream-rules
+func (c *Client) AddStreamRules(rules []entities.StreamRule, dry_run bool) (*StreamRulesResponse, error) {
+	return c.AddStreamRulesCtx(context.Background(), rules, dry_run)
+}
+
+// Same as AddStreamRules, but the request is bound to ctx.
+func (c *Client) AddStreamRulesCtx(ctx context.Context, rules []entities.StreamRule, dry_run bool) (*StreamRulesResponse, error) {
+	if err := c.check_stream_rules(rules); err != nil {
+		return nil, err
+	}
+
+	add := []Map{}
+	for _, rule := range rules {
+		new_rule := Map{"value": rule.Value}
+		if rule.Tag != "" {
+			new_rule["tag"] = rule.Tag
+		}
+		add = append(add, new_rule)
+	}
+
+	return c.stream_rules_request(ctx, "AddStreamRules", Map{"add": add}, dry_run)
+}
+
+// Deletes rules of the filtered stream by their IDs.
+//
+// Parameters
+//
+// ids: IDs of the rules to be deleted.
+//
+// dry_run: Only validates the request, nothing will be deleted.
+//
+// References
+//
+// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/post-tweets-search-stream-rules
+func (c *Client) DeleteStreamRules(ids []string, dry_run bool) (*StreamRulesResponse, error) {
+	return c.DeleteStreamRulesCtx(context.Background(), ids, dry_run)
+}
+
+// Same as DeleteStreamRules, but the request is bound to ctx.
+func (c *Client) DeleteStreamRulesCtx(ctx context.Context, ids []string, dry_run bool) (*StreamRulesResponse, error) {
+	return c.stream_rules_request(ctx, "DeleteStreamRules", Map{"delete": Map{"ids": ids}}, dry_run)
+}
+
+// Checks the rules without adding them, invalid rules are reported in the Errors of the response.
+func (c *Client) ValidateStreamRules(rules []entities.StreamRule) (*StreamRulesResponse, error) {
+	return c.ValidateStreamRulesCtx(context.Background(), rules)
+}
+
+// Same as ValidateStreamRules, but the request is bound to ctx.
+func (c *Client) ValidateStreamRulesCtx(ctx context.Context, rules []entities.StreamRule) (*StreamRulesResponse, error) {
+	return c.AddStreamRulesCtx(ctx, rules, true)
+}
+
+// The changes SyncStreamRules makes, or would make in a dry run.
+type StreamRulesSync struct {
+	// Desired rules that are not installed yet.
+	Add []entities.StreamRule
+	// Installed rules that are not desired anymore, or duplicates.
+	Delete []entities.StreamRule
+	// Installed rules that are desired, with their IDs.
+	Keep []entities.StreamRule
+	// Rules that are actually added, with the IDs Twitter assigned to them, empty in a dry run.
+	Added []entities.StreamRule
+	// Errors reported by Twitter for the rules that couldn't be added, or are invalid in a dry run.
+	Errors []entities.PartialError
+}
+
+// Makes the installed stream rules exactly like desired ones,
+// rules are matched on both value and tag, so changing the tag of a rule replaces it.
+//
+// The new rules are validated by Twitter before any rule is deleted, so a rejected rule never leaves the stream without rules,
+// except the ones with the value of a stale rule, like a rule whose tag changes, Twitter rejects them until the stale one is deleted.
+// They are added before deleting the stale ones if the rules limit of the access level set by WithAccessLevel allows it,
+// otherwise the stale ones are deleted first, so the limit is respected.
+//
+// Parameters
+//
+// desired: Rules that should be installed, their IDs are ignored.
+//
+// dry_run: Only computes the changes and validates the new rules, nothing will be changed.
+func (c *Client) SyncStreamRules(desired []entities.StreamRule, dry_run bool) (*StreamRulesSync, error) {
+	return c.SyncStreamRulesCtx(context.Background(), desired, dry_run)
+}
+
+// Same as SyncStreamRules, but the requests are bound to ctx.
+func (c *Client) SyncStreamRulesCtx(ctx context.Context, desired []entities.StreamRule, dry_run bool) (*StreamRulesSync, error) {
+	installed, err := c.GetStreamRulesCtx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sync := plan_stream_rules(desired, installed.Data)
+
+	// Nothing should be deleted if the new rules can't be added.
+	if err := c.check_stream_rules(sync.Add); err != nil {
+		return sync, err
+	}
+
+	if dry_run || !c.can_add_stream_rules_first(sync) {
+		// Rules with the value of a stale one would be rejected as duplicates while it's installed,
+		// their value is already accepted by Twitter anyway.
+		if err := c.add_synced_stream_rules(ctx, sync, new_stream_rule_values(sync), true); err != nil || dry_run {
+			return sync, err
+		}
+		if err := c.delete_synced_stream_rules(ctx, sync); err != nil {
+			return sync, err
+		}
+		return sync, c.add_synced_stream_rules(ctx, sync, sync.Add, false)
+	}
+
+	if err := c.add_synced_stream_rules(ctx, sync, sync.Add, false); err != nil {
+		return sync, err
+	}
+	return sync, c.delete_synced_stream_rules(ctx, sync)
+}
+
+// Whether or not the new rules can be added while the stale ones are still installed,
+// the rules limit must be known, and Twitter rejects a rule with the value of an installed one, even with another tag.
+func (c *Client) can_add_stream_rules_first(sync *StreamRulesSync) bool {
+	if c.accessLevel == nil || len(sync.Keep)+len(sync.Delete)+len(sync.Add) > c.accessLevel.MaxRules() {
+		return false
+	}
+	return len(new_stream_rule_values(sync)) == len(sync.Add)
+}
+
+// Returns the rules of sync.Add whose value is not the value of a stale rule, like the ones only changing the tag.
+func new_stream_rule_values(sync *StreamRulesSync) []entities.StreamRule {
+	stale := map[string]bool{}
+	for _, rule := range sync.Delete {
+		stale[rule.Value] = true
+	}
+
+	rules := []entities.StreamRule{}
+	for _, rule := range sync.Add {
+		if !stale[rule.Value] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Adds the rules of sync, or only validates them in a dry run,
+// an error is returned if Twitter doesn't accept all of them.
+func (c *Client) add_synced_stream_rules(ctx context.Context, sync *StreamRulesSync, rules []entities.StreamRule, dry_run bool) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	response, err := c.AddStreamRulesCtx(ctx, rules, dry_run)
+	if err != nil {
+		return err
+	}
+	sync.Errors = response.Errors
+	if !dry_run {
+		sync.Added = response.Data
+	}
+	if len(response.Errors) != 0 {
+		return fmt.Errorf("twigo: %d stream rules were not accepted", len(response.Errors))
+	}
+	return nil
+}
+
+// Deletes the stale rules of sync.
+func (c *Client) delete_synced_stream_rules(ctx context.Context, sync *StreamRulesSync) error {
+	if len(sync.Delete) == 0 {
+		return nil
+	}
+
+	ids := []string{}
+	for _, rule := range sync.Delete {
+		ids = append(ids, rule.ID)
+	}
+	_, err := c.DeleteStreamRulesCtx(ctx, ids, false)
+	return err
+}
+
+// Computes the difference between the desired and the installed rules.
+func plan_stream_rules(desired, installed []entities.StreamRule) *StreamRulesSync {
+	key := func(rule entities.StreamRule) string {
+		return rule.Value + "\x00" + rule.Tag
+	}
+
+	sync := &StreamRulesSync{}
+	wanted := map[string]bool{}
+	for _, rule := range desired {
+		if k := key(rule); !wanted[k] {
+			wanted[k] = true
+			sync.Add = append(sync.Add, entities.StreamRule{Value: rule.Value, Tag: rule.Tag})
+		}
+	}
+
+	kept := map[string]bool{}
+	for _, rule := range installed {
+		k := key(rule)
+		if wanted[k] && !kept[k] {
+			kept[k] = true
+			sync.Keep = append(sync.Keep, rule)
+		} else {
+			sync.Delete = append(sync.Delete, rule)
+		}
+	}
+
+	add := sync.Add[:0]
+	for _, rule := range sync.Add {
+		if !kept[key(rule)] {
+			add = append(add, rule)
+		}
+	}
+	sync.Add = add
+
+	return sync
+}
+
+// Checks the values of the rules before they are sent, see check_query.
+func (c *Client) check_stream_rules(rules []entities.StreamRule) error {
+	for _, rule := range rules {
+		if err := c.check_query(rule.Value, false); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Value, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package twigo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arshamalh/twigo/entities"
+	"github.com/arshamalh/twigo/query"
+	"github.com/arshamalh/twigo/utils"
+)
+
+// A fake rules endpoint, which rejects a rule with the value of an installed one, like Twitter does.
+type fake_stream_rules struct {
+	mu        sync.Mutex
+	installed []entities.StreamRule
+	next_id   int
+	// Requests it received, like "add golang/go", "dry_run add golang/go" or "delete 1".
+	log []string
+}
+
+func (f *fake_stream_rules) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method == "GET" {
+		json.NewEncoder(w).Encode(Map{"data": f.installed})
+		return
+	}
+
+	var body struct {
+		Add    []entities.StreamRule
+		Delete struct{ IDs []string }
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	prefix := ""
+	dry_run := r.URL.Query().Get("dry_run") == "true"
+	if dry_run {
+		prefix = "dry_run "
+	}
+
+	if len(body.Delete.IDs) != 0 {
+		f.log = append(f.log, prefix+"delete "+strings.Join(body.Delete.IDs, ","))
+		installed := []entities.StreamRule{}
+		for _, rule := range f.installed {
+			if dry_run || !utils.Contains(body.Delete.IDs, rule.ID) {
+				installed = append(installed, rule)
+			}
+		}
+		f.installed = installed
+		json.NewEncoder(w).Encode(Map{})
+		return
+	}
+
+	added, errors, rules := []entities.StreamRule{}, []Map{}, []string{}
+	for _, rule := range body.Add {
+		rules = append(rules, rule.Value+"/"+rule.Tag)
+		if f.has_value(rule.Value) {
+			errors = append(errors, Map{"title": "DuplicateRule", "value": rule.Value})
+			continue
+		}
+		if strings.HasPrefix(rule.Value, "invalid") {
+			errors = append(errors, Map{"title": "UnprocessableEntity", "value": rule.Value})
+			continue
+		}
+		if !dry_run {
+			f.next_id++
+			rule.ID = fmt.Sprint(f.next_id)
+			f.installed = append(f.installed, rule)
+			added = append(added, rule)
+		}
+	}
+	f.log = append(f.log, prefix+"add "+strings.Join(rules, ","))
+	json.NewEncoder(w).Encode(Map{"data": added, "errors": errors})
+}
+
+func (f *fake_stream_rules) has_value(value string) bool {
+	for _, rule := range f.installed {
+		if rule.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSyncStreamRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed []entities.StreamRule
+		desired   []entities.StreamRule
+		options   []ClientOption
+		dry_run   bool
+		want_log  []string
+		want      []string
+	}{
+		{
+			name:      "retag",
+			installed: []entities.StreamRule{{ID: "100", Value: "golang", Tag: "old"}},
+			desired:   []entities.StreamRule{{Value: "golang", Tag: "new"}},
+			want_log:  []string{"delete 100", "add golang/new"},
+			want:      []string{"golang/new"},
+		},
+		{
+			name:      "retag in a dry run",
+			installed: []entities.StreamRule{{ID: "100", Value: "golang", Tag: "old"}},
+			desired:   []entities.StreamRule{{Value: "golang", Tag: "new"}},
+			dry_run:   true,
+			want:      []string{"golang/old"},
+		},
+		{
+			name: "retag and new rules",
+			installed: []entities.StreamRule{
+				{ID: "100", Value: "golang", Tag: "old"},
+				{ID: "101", Value: "rust", Tag: ""},
+			},
+			desired:  []entities.StreamRule{{Value: "golang", Tag: "new"}, {Value: "gopher", Tag: ""}},
+			want_log: []string{"dry_run add gopher/", "delete 100,101", "add golang/new,gopher/"},
+			want:     []string{"golang/new", "gopher/"},
+		},
+		{
+			name:      "new rules first when the limit allows it",
+			installed: []entities.StreamRule{{ID: "100", Value: "rust", Tag: ""}},
+			desired:   []entities.StreamRule{{Value: "gopher", Tag: ""}},
+			options:   []ClientOption{WithAccessLevel(query.Elevated)},
+			want_log:  []string{"add gopher/", "delete 100"},
+			want:      []string{"gopher/"},
+		},
+		{
+			name:      "retag with a known limit",
+			installed: []entities.StreamRule{{ID: "100", Value: "golang", Tag: "old"}},
+			desired:   []entities.StreamRule{{Value: "golang", Tag: "new"}},
+			options:   []ClientOption{WithAccessLevel(query.Elevated)},
+			want_log:  []string{"delete 100", "add golang/new"},
+			want:      []string{"golang/new"},
+		},
+		{
+			name:      "nothing to change",
+			installed: []entities.StreamRule{{ID: "100", Value: "golang", Tag: "go"}},
+			desired:   []entities.StreamRule{{Value: "golang", Tag: "go"}},
+			want:      []string{"golang/go"},
+		},
+	}
+	for _, test := range tests {
+		rules := &fake_stream_rules{installed: test.installed, next_id: 1000}
+		server := httptest.NewServer(rules)
+		client, _ := NewBearerOnlyClient("token", append([]ClientOption{WithBaseURL(server.URL)}, test.options...)...)
+
+		_, err := client.SyncStreamRules(test.desired, test.dry_run)
+		server.Close()
+		if err != nil {
+			t.Errorf("%s: error: %v", test.name, err)
+			continue
+		}
+
+		if got := strings.Join(rules.log, "; "); got != strings.Join(test.want_log, "; ") {
+			t.Errorf("%s: requests = %q, want %q", test.name, got, strings.Join(test.want_log, "; "))
+		}
+		installed := []string{}
+		for _, rule := range rules.installed {
+			installed = append(installed, rule.Value+"/"+rule.Tag)
+		}
+		if got := strings.Join(installed, ","); got != strings.Join(test.want, ",") {
+			t.Errorf("%s: installed = %q, want %q", test.name, got, strings.Join(test.want, ","))
+		}
+	}
+}
+
+func TestSyncStreamRulesRejected(t *testing.T) {
+	rules := &fake_stream_rules{installed: []entities.StreamRule{{ID: "100", Value: "golang", Tag: "old"}}}
+	server := httptest.NewServer(rules)
+	defer server.Close()
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+
+	sync, err := client.SyncStreamRules([]entities.StreamRule{{Value: "golang", Tag: "new"}, {Value: "invalid rule"}}, false)
+	if err == nil {
+		t.Fatal("no error for a rejected rule")
+	}
+	if len(sync.Errors) != 1 {
+		t.Errorf("Errors = %v, want 1 error", sync.Errors)
+	}
+	if got := strings.Join(rules.log, "; "); got != "dry_run add invalid rule/" {
+		t.Errorf("requests = %q, want only the dry run", got)
+	}
+	if len(rules.installed) != 1 || rules.installed[0].ID != "100" {
+		t.Errorf("installed = %v, want rule 100 to stay", rules.installed)
+	}
+}
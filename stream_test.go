@@ -0,0 +1,344 @@
+package twigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A streaming endpoint, every connection is answered by the next function of connections,
+// the last one answers the rest of them.
+type fake_stream struct {
+	mu          sync.Mutex
+	connections []func(w http.ResponseWriter, r *http.Request)
+	requests    []*http.Request
+}
+
+func (f *fake_stream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	connection := f.connections[0]
+	if len(f.connections) > 1 {
+		f.connections = f.connections[1:]
+	}
+	f.requests = append(f.requests, r)
+	f.mu.Unlock()
+
+	connection(w, r)
+}
+
+func (f *fake_stream) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+// Sends the lines, then closes the connection.
+func stream_lines(lines ...string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		for _, line := range lines {
+			fmt.Fprint(w, line+"\r\n")
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+// Sends the lines, then keeps the connection open until the client closes it.
+func stream_lines_and_hang(lines ...string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stream_lines(lines...)(w, r)
+		<-r.Context().Done()
+	}
+}
+
+func stream_status(status int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}
+}
+
+func new_test_stream(t *testing.T, connections ...func(w http.ResponseWriter, r *http.Request)) (*Stream, *fake_stream) {
+	fake := &fake_stream{connections: connections}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+	return client.FilteredStream(nil), fake
+}
+
+func TestStreamReconnects(t *testing.T) {
+	stream, fake := new_test_stream(t,
+		stream_lines(`{"data":{"id":"1","text":"first"}}`, ""),
+		stream_lines_and_hang(`{"data":{"id":"2","text":"second"},"matching_rules":[{"id":"10","tag":"go"}]}`),
+	)
+	disconnects := []error{}
+	stream.OnDisconnect = func(err error) {
+		disconnects = append(disconnects, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tweets := []*StreamTweet{}
+	err := stream.Run(ctx, func(tweet *StreamTweet) {
+		tweets = append(tweets, tweet)
+		if len(tweets) == 2 {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tweets) != 2 || tweets[0].Data.ID != "1" || tweets[1].Data.ID != "2" {
+		t.Fatalf("tweets = %v, want Tweets 1 and 2", tweets)
+	}
+	if len(tweets[1].MatchingRules) != 1 || tweets[1].MatchingRules[0].Tag != "go" {
+		t.Errorf("matching rules = %v, want the rule tagged go", tweets[1].MatchingRules)
+	}
+	if len(disconnects) != 1 || !errors.Is(disconnects[0], ErrStreamDisconnected) {
+		t.Errorf("disconnects = %v, want one %v", disconnects, ErrStreamDisconnected)
+	}
+	stats := stream.Stats()
+	if fake.count() != 2 || stats.Connections != 2 || stats.Tweets != 2 {
+		t.Errorf("%d requests, stats = %+v, want 2 connections and 2 Tweets", fake.count(), stats)
+	}
+	if stats.LastKeepAlive.IsZero() {
+		t.Error("the keep-alive signal is not recorded")
+	}
+}
+
+func TestStreamReconnectsAfterStall(t *testing.T) {
+	stream, fake := new_test_stream(t, stream_lines_and_hang())
+	stream.KeepAliveTimeout = 20 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	disconnects := 0
+	stream.OnDisconnect = func(err error) {
+		if !errors.Is(err, ErrStreamStalled) {
+			t.Errorf("disconnect error = %v, want %v", err, ErrStreamStalled)
+		}
+		disconnects++
+		if disconnects == 2 {
+			cancel()
+		}
+	}
+	if err := stream.Run(ctx, func(tweet *StreamTweet) {}); err != nil {
+		t.Fatal(err)
+	}
+	if fake.count() != 2 {
+		t.Errorf("%d connections, want 2", fake.count())
+	}
+}
+
+func TestStreamOperationalDisconnect(t *testing.T) {
+	stream, _ := new_test_stream(t,
+		stream_lines(`{"errors":[{"title":"operational-disconnect","detail":"This stream has been disconnected for operational reasons."}]}`),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got error
+	stream.OnDisconnect = func(err error) {
+		got = err
+		cancel()
+	}
+	if err := stream.Run(ctx, func(tweet *StreamTweet) {}); err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(got, ErrStreamDisconnected) {
+		t.Errorf("disconnect error = %v, want %v", got, ErrStreamDisconnected)
+	}
+}
+
+func TestStreamMaxReconnects(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the backoff")
+	}
+	stream, fake := new_test_stream(t, stream_status(http.StatusServiceUnavailable))
+	stream.MaxReconnects = 1
+
+	// The second failed connection is one reconnection too many, after 5 seconds of backoff.
+	err := stream.Run(context.Background(), func(tweet *StreamTweet) {})
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("error = %v, want %v", err, ErrServerError)
+	}
+	if fake.count() != 2 {
+		t.Errorf("%d connections, want 2", fake.count())
+	}
+}
+
+func TestStreamGivesUp(t *testing.T) {
+	stream, fake := new_test_stream(t, stream_status(http.StatusUnauthorized))
+
+	err := stream.Run(context.Background(), func(tweet *StreamTweet) {})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("error = %v, want %v", err, ErrUnauthorized)
+	}
+	if fake.count() != 1 {
+		t.Errorf("%d connections, want 1", fake.count())
+	}
+}
+
+func TestStreamBackoff(t *testing.T) {
+	network_error := errors.New("connection reset")
+	server_error := &APIError{}
+	server_error.StatusCode = http.StatusServiceUnavailable
+	rate_limited := &APIError{}
+	rate_limited.StatusCode = http.StatusTooManyRequests
+	forbidden := &APIError{}
+	forbidden.StatusCode = http.StatusForbidden
+
+	tests := []struct {
+		name string
+		errs []error
+		// Expected wait after each error, a negative one means giving up.
+		want []time.Duration
+	}{
+		{
+			name: "network errors grow linearly",
+			errs: []error{network_error, ErrStreamStalled, network_error},
+			want: []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, 750 * time.Millisecond},
+		},
+		{
+			name: "server errors grow exponentially",
+			errs: []error{server_error, server_error, server_error},
+			want: []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second},
+		},
+		{
+			name: "rate limits start from a minute",
+			errs: []error{rate_limited, rate_limited},
+			want: []time.Duration{time.Minute, 2 * time.Minute},
+		},
+		{
+			name: "tiers are separate",
+			errs: []error{network_error, server_error, network_error},
+			want: []time.Duration{250 * time.Millisecond, 5 * time.Second, 500 * time.Millisecond},
+		},
+		{
+			name: "client errors give up",
+			errs: []error{forbidden},
+			want: []time.Duration{-1},
+		},
+	}
+	for _, test := range tests {
+		backoff := stream_backoff{}
+		for i, err := range test.errs {
+			wait, ok := backoff.next(err)
+			if !ok {
+				wait = -1
+			}
+			if wait != test.want[i] {
+				t.Errorf("%s: wait %d = %s, want %s", test.name, i, wait, test.want[i])
+			}
+		}
+	}
+
+	// Network backoff is capped at 16 seconds, and server errors at 320 seconds.
+	backoff := stream_backoff{}
+	for i := 0; i < 100; i++ {
+		backoff.next(network_error)
+		backoff.next(server_error)
+	}
+	if wait, _ := backoff.next(network_error); wait != 16*time.Second {
+		t.Errorf("network backoff = %s, want 16s", wait)
+	}
+	if wait, _ := backoff.next(server_error); wait != 320*time.Second {
+		t.Errorf("server error backoff = %s, want 320s", wait)
+	}
+}
+
+func TestStreamTweets(t *testing.T) {
+	fake := &fake_stream{connections: []func(w http.ResponseWriter, r *http.Request){
+		stream_lines(`{"data":{"id":"1","text":"first"}}`, `{"data":{"id":"2","text":"second"}}`),
+		stream_status(http.StatusUnauthorized),
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+	stream := client.SampleStream(Map{"tweet.fields": "created_at"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ids := []string{}
+	for tweet := range stream.Tweets(ctx) {
+		ids = append(ids, tweet.Data.ID)
+	}
+
+	if fmt.Sprint(ids) != "[1 2]" {
+		t.Errorf("Tweets = %v, want [1 2]", ids)
+	}
+	if err := stream.Err(); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Err() = %v, want %v", err, ErrUnauthorized)
+	}
+	request := fake.requests[0]
+	if request.URL.Path != "/2/tweets/sample/stream" || request.URL.Query().Get("tweet.fields") != "created_at" {
+		t.Errorf("request = %s, want the sample stream with its params", request.URL)
+	}
+}
+
+func TestStreamBackfillOnReconnect(t *testing.T) {
+	stream, fake := new_test_stream(t,
+		stream_lines(`{"data":{"id":"1","text":"first"}}`),
+		stream_lines_and_hang(`{"data":{"id":"2","text":"second"}}`),
+	)
+	stream.BackfillOnReconnect = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := stream.Run(ctx, func(tweet *StreamTweet) {
+		if tweet.Data.ID == "2" {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fake.count() != 2 {
+		t.Fatalf("%d connections, want 2", fake.count())
+	}
+	if backfill := fake.requests[0].URL.Query().Get("backfill_minutes"); backfill != "" {
+		t.Errorf("backfill_minutes of the first connection = %q, want none", backfill)
+	}
+	if backfill := fake.requests[1].URL.Query().Get("backfill_minutes"); backfill != "1" {
+		t.Errorf("backfill_minutes of the reconnection = %q, want 1", backfill)
+	}
+	if stream.Params["backfill_minutes"] != nil {
+		t.Error("backfill_minutes is added to the params of the stream")
+	}
+
+	stats := stream.Stats()
+	if !errors.Is(stats.LastDisconnect, ErrStreamDisconnected) || len(stats.Disconnects) != 1 {
+		t.Errorf("stats = %+v, want one disconnection", stats)
+	}
+	if stats.Connections != 2 || stats.Tweets != 2 || stats.LastTweet.IsZero() {
+		t.Errorf("stats = %+v, want 2 connections and 2 Tweets", stats)
+	}
+}
+
+func TestSample10Stream(t *testing.T) {
+	fake := &fake_stream{connections: []func(w http.ResponseWriter, r *http.Request){
+		stream_status(http.StatusForbidden),
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+	params := Map{"expansions": "author_id"}
+	stream := client.Sample10Stream(2, params)
+
+	if err := stream.Run(context.Background(), func(tweet *StreamTweet) {}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("error = %v, want %v", err, ErrForbidden)
+	}
+	query := fake.requests[0].URL.Query()
+	if fake.requests[0].URL.Path != "/2/tweets/sample10/stream" || query.Get("partition") != "2" || query.Get("expansions") != "author_id" {
+		t.Errorf("request = %s, want partition 2 of the 10%% stream with its params", fake.requests[0].URL)
+	}
+	if _, ok := params["partition"]; ok {
+		t.Error("the partition is added to the given params")
+	}
+}
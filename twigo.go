@@ -15,12 +15,21 @@ type Config struct {
 	BearerToken    string
 }
 
-func NewClient(config *Config) (*Client, error) {
-	keys_exists := config.ConsumerKey != "" && config.ConsumerSecret != "" && config.AccessToken != "" && config.AccessSecret == ""
+func NewClient(config *Config, opts ...ClientOption) (*Client, error) {
+	keys_exists := config.ConsumerKey != "" && config.ConsumerSecret != "" && config.AccessToken != "" && config.AccessSecret != ""
+
+	client := &Client{baseURL: default_base_url}
+	client.applyOptions(opts)
 
 	if !keys_exists {
 		if config.BearerToken == "" {
-			if bearer_token, err := utils.BearerFinder(config.ConsumerKey, config.ConsumerSecret); err == nil {
+			if bearer_token, err := utils.BearerFinderWithClient(
+				client.httpClient,
+				client.baseURL+"oauth2/token",
+				client.userAgent,
+				config.ConsumerKey,
+				config.ConsumerSecret,
+			); err == nil {
 				config.BearerToken = bearer_token
 			} else {
 				return nil, err
@@ -32,30 +41,15 @@ func NewClient(config *Config) (*Client, error) {
 			userID = strings.Split(config.AccessToken, "-")[0]
 		}
 
-		return &Client{
-			nil,
-			"",
-			"",
-			"",
-			"",
-			config.BearerToken,
-			true,
-			userID,
-			OAuth_2,
-		}, nil
+		client.bearerToken = config.BearerToken
+		client.userID = userID
+		return client, nil
 	}
 
 	userID := strings.Split(config.AccessToken, "-")[0]
 
 	// TODO: I'm authenticating here, but Do I need to authenticate every once in a while?
-	consumer := oauth.NewConsumer(
-		config.ConsumerKey,
-		config.ConsumerSecret,
-		oauth.ServiceProvider{
-			RequestTokenUrl:   "https://api.twitter.com/oauth/request_token",
-			AuthorizeTokenUrl: "https://api.twitter.com/oauth/authorize",
-			AccessTokenUrl:    "https://api.twitter.com/oauth/access_token",
-		})
+	consumer := client.new_consumer(config.ConsumerKey, config.ConsumerSecret)
 
 	t := oauth.AccessToken{
 		Token:  config.AccessToken,
@@ -63,29 +57,36 @@ func NewClient(config *Config) (*Client, error) {
 	}
 
 	authorizedClient, err := consumer.MakeHttpClient(&t)
-	return &Client{
-		authorizedClient,
-		config.ConsumerKey,
-		config.ConsumerSecret,
-		config.AccessToken,
-		config.AccessSecret,
-		config.BearerToken,
-		false,
-		userID,
-		OAuth_Default,
-	}, err
+	client.authorizedClient = authorizedClient
+	client.consumerKey = config.ConsumerKey
+	client.consumerSecret = config.ConsumerSecret
+	client.accessToken = config.AccessToken
+	client.accessTokenSecret = config.AccessSecret
+	client.bearerToken = config.BearerToken
+	client.userID = userID
+	return client, err
+}
+
+// Returns the OAuth 1.0a consumer of the app, using the base URL and the http client of c.
+func (c *Client) new_consumer(consumer_key, consumer_secret string) *oauth.Consumer {
+	return oauth.NewCustomHttpClientConsumer(
+		consumer_key,
+		consumer_secret,
+		oauth.ServiceProvider{
+			RequestTokenUrl:   c.baseURL + "oauth/request_token",
+			AuthorizeTokenUrl: c.baseURL + "oauth/authorize",
+			AccessTokenUrl:    c.baseURL + "oauth/access_token",
+			HttpMethod:        "POST",
+		},
+		c.httpClient,
+	)
 }
 
-func NewBearerOnlyClient(bearerToken string) (*Client, error) {
-	return &Client{
-		nil,
-		"",
-		"",
-		"",
-		"",
-		bearerToken,
-		true,
-		"",
-		OAuth_2,
-	}, nil
+func NewBearerOnlyClient(bearerToken string, opts ...ClientOption) (*Client, error) {
+	client := &Client{
+		baseURL:     default_base_url,
+		bearerToken: bearerToken,
+	}
+	client.applyOptions(opts)
+	return client, nil
 }
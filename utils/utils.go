@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +23,44 @@ func Contains(arrayOfStrings []string, string_item string) bool {
 	return false
 }
 
+// Whether or not the Tweet ID a is newer than b, IDs grow with time,
+// they are compared as numbers, without parsing them, so IDs of any length work.
+func NewerID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
+
+// Replaces the file at path with data, like os.WriteFile,
+// but a crash never leaves a half written file, and concurrent writers don't mix their data.
+//
+// The data is written to a new temporary file next to path, synced to the disk, and renamed to path.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	temp_file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Does nothing after the rename.
+	defer os.Remove(temp_file.Name())
+
+	if _, err := temp_file.Write(data); err != nil {
+		temp_file.Close()
+		return err
+	}
+	if err := temp_file.Sync(); err != nil {
+		temp_file.Close()
+		return err
+	}
+	if err := temp_file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp_file.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(temp_file.Name(), path)
+}
+
 type BearerToken struct {
 	TokenType   string `json:"token_type"`
 	AccessToken string `json:"access_token"`
@@ -28,12 +68,24 @@ type BearerToken struct {
 
 // Utility function to get the bearer token using the client_id and client_secret
 func BearerFinder(ConsumerKey, ConsumerSecret string) (string, error) {
+	return BearerFinderWithClient(
+		&http.Client{},
+		"https://api.twitter.com/oauth2/token",
+		"",
+		ConsumerKey,
+		ConsumerSecret,
+	)
+}
+
+// Same as BearerFinder, but sends the request to token_url using the given http client,
+// user_agent is not set if it's empty.
+func BearerFinderWithClient(client *http.Client, token_url, user_agent, ConsumerKey, ConsumerSecret string) (string, error) {
 	credentials := ConsumerKey + ":" + ConsumerSecret
 	credentialsBase64Encoded := base64.StdEncoding.EncodeToString([]byte(credentials))
 
 	request, err := http.NewRequest(
 		"POST",
-		"https://api.twitter.com/oauth2/token",
+		token_url,
 		strings.NewReader("grant_type=client_credentials"),
 	)
 
@@ -43,15 +95,21 @@ func BearerFinder(ConsumerKey, ConsumerSecret string) (string, error) {
 
 	request.Header.Set("Authorization", fmt.Sprintf("Basic %s", credentialsBase64Encoded))
 	request.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
-	client := &http.Client{}
+	if user_agent != "" {
+		request.Header.Set("User-Agent", user_agent)
+	}
 	response, err := client.Do(request)
 
-	if response.StatusCode != 200 || err != nil {
-		return "", fmt.Errorf("error code: %d, error: %s", response.StatusCode, err)
+	if err != nil {
+		return "", err
 	}
 
 	defer response.Body.Close()
 
+	if response.StatusCode != 200 {
+		return "", fmt.Errorf("error code: %d", response.StatusCode)
+	}
+
 	bearer_token := &BearerToken{}
 	err = json.NewDecoder(response.Body).Decode(bearer_token)
 
@@ -70,26 +128,73 @@ func QueryValue(params []string) string {
 	return strings.Join(params, ",")
 }
 
+// Makes a query string out of params, see QueryBuilder for the details.
+//
+// Deprecated: Use QueryBuilder, unsupported parameters and values make an empty query here, without an error.
 func QueryMaker(params map[string]interface{}, endpoint_parameters []string) string {
+	query, _ := QueryBuilder(params, endpoint_parameters)
+	return query
+}
+
+// Makes a query string out of params,
+// an underscore in the name of a parameter can be used instead of its first dot, like "tweet_fields".
+//
+// Values can be strings, booleans, numbers, slices of strings or integers, time.Time or any fmt.Stringer,
+// an error is returned for any other value or a parameter that is not in endpoint_parameters.
+func QueryBuilder(params map[string]interface{}, endpoint_parameters []string) (string, error) {
 	parameters := url.Values{}
 	for param_name, param_value := range params {
 		if new_param_name := strings.Replace(param_name, "_", ".", 1); Contains(endpoint_parameters, new_param_name) {
 			param_name = new_param_name
 		} else if !Contains(endpoint_parameters, param_name) {
-			fmt.Printf("it seems endpoint parameter '%s' is not supported", param_name)
+			return "", fmt.Errorf("endpoint parameter '%s' is not supported", param_name)
+		}
+
+		value, err := QueryValueOf(param_value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", param_name, err)
+		}
+		parameters.Add(param_name, value)
+	}
+	return parameters.Encode(), nil
+}
+
+// Converts a parameter value to its query string form.
+func QueryValueOf(param_value interface{}) (string, error) {
+	switch param_valt := param_value.(type) {
+	case string:
+		return param_valt, nil
+	case bool:
+		return strconv.FormatBool(param_valt), nil
+	case int:
+		return strconv.Itoa(param_valt), nil
+	case int8, int16, int32, int64:
+		return fmt.Sprint(param_valt), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(param_valt), nil
+	case float32:
+		return strconv.FormatFloat(float64(param_valt), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(param_valt, 'f', -1, 64), nil
+	case []string:
+		return strings.Join(param_valt, ","), nil
+	case []int:
+		values := make([]string, 0, len(param_valt))
+		for _, value := range param_valt {
+			values = append(values, strconv.Itoa(value))
 		}
-		switch param_valt := param_value.(type) {
-		case int:
-			parameters.Add(param_name, strconv.Itoa(param_valt))
-		case string:
-			parameters.Add(param_name, param_valt)
-		case []string:
-			parameters.Add(param_name, strings.Join(param_valt, ","))
-		case time.Time:
-			parameters.Add(param_name, param_valt.Format(time.RFC3339))
-		default:
-			fmt.Printf("%s with value of %s is not supported, please contact us", param_name, param_value)
+		return strings.Join(values, ","), nil
+	case []int64:
+		values := make([]string, 0, len(param_valt))
+		for _, value := range param_valt {
+			values = append(values, strconv.FormatInt(value, 10))
 		}
+		return strings.Join(values, ","), nil
+	case time.Time:
+		return param_valt.UTC().Format(time.RFC3339), nil
+	case fmt.Stringer:
+		return param_valt.String(), nil
+	default:
+		return "", fmt.Errorf("value %v of type %T is not supported", param_value, param_value)
 	}
-	return parameters.Encode()
 }
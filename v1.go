@@ -0,0 +1,28 @@
+package twigo
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/arshamalh/twigo/v1"
+)
+
+// Returns the client of the v1.1 endpoints v2 doesn't have yet, like trends and geo,
+// it uses the OAuth 1.0a keys, the options, the retry policy and the rate limiter of c,
+// and its errors are *APIError too.
+//
+//	trends, err := client.V1().Trends(1, false)
+func (c *Client) V1() *v1.Client {
+	return v1.New(v1.DoerFunc(c.do_v1), c.baseURL)
+}
+
+// Sends a request of the v1 client, signed with OAuth 1.0a.
+func (c *Client) do_v1(request *http.Request) (*http.Response, error) {
+	if !c.has_credentials(OAuth_1a) {
+		return nil, fmt.Errorf("%w: v1.1 endpoints need %s", ErrNoCredentials, OAuth_1a)
+	}
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+	return c.do(c.authorizedClient, request.WithContext(ContextWithOAuth(request.Context(), OAuth_1a)))
+}
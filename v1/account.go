@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// The authenticated user, with their latest Tweet and email address, when they are asked for.
+type Account struct {
+	User entities.User
+	// Nil if it was skipped, or the user has no Tweets.
+	Status *entities.Tweet
+	// Only set if it was asked for, and the app has the permission to read it.
+	Email string
+}
+
+func (u *user) to_account() *Account {
+	account := &Account{User: u.to_entity(), Email: u.Email}
+	if u.Status != nil {
+		status := u.Status.to_entity()
+		status.AuthorID = account.User.ID
+		account.Status = &status
+	}
+	return account
+}
+
+// Options of VerifyCredentials.
+type VerifyCredentialsOptions struct {
+	IncludeEmail bool
+	SkipStatus   bool
+}
+
+// Returns the authenticated user if the credentials are valid, an error with the 401 status code otherwise.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/manage-account-settings/api-reference/get-account-verify_credentials
+func (c *Client) VerifyCredentials(options *VerifyCredentialsOptions) (*Account, error) {
+	return c.VerifyCredentialsCtx(context.Background(), options)
+}
+
+// Same as VerifyCredentials, but the request is bound to ctx.
+func (c *Client) VerifyCredentialsCtx(ctx context.Context, options *VerifyCredentialsOptions) (*Account, error) {
+	params := url.Values{"tweet_mode": {"extended"}}
+	if options != nil && options.IncludeEmail {
+		params.Set("include_email", "true")
+	}
+	if options != nil && options.SkipStatus {
+		params.Set("skip_status", "true")
+	}
+
+	account := user{}
+	if err := c.request(ctx, "GET", "account/verify_credentials.json", params, &account); err != nil {
+		return nil, err
+	}
+	return account.to_account(), nil
+}
+
+// Fields of the profile to update, empty ones are left as they are.
+type Profile struct {
+	// Up to 50 characters.
+	Name string
+	// Up to 100 characters.
+	URL string
+	// Up to 30 characters.
+	Location string
+	// Up to 160 characters.
+	Description string
+}
+
+// Updates the profile of the authenticated user, and returns it.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/manage-account-settings/api-reference/post-account-update_profile
+func (c *Client) UpdateProfile(profile *Profile) (*Account, error) {
+	return c.UpdateProfileCtx(context.Background(), profile)
+}
+
+// Same as UpdateProfile, but the request is bound to ctx.
+func (c *Client) UpdateProfileCtx(ctx context.Context, profile *Profile) (*Account, error) {
+	if profile == nil || *profile == (Profile{}) {
+		return nil, invalid_params("profile has nothing to update")
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"name", profile.Name, 50},
+		{"url", profile.URL, 100},
+		{"location", profile.Location, 30},
+		{"description", profile.Description, 160},
+	} {
+		if len([]rune(field.value)) > field.max {
+			return nil, invalid_params("%s can't be longer than %d characters", field.name, field.max)
+		}
+	}
+
+	params := url.Values{"skip_status": {"true"}}
+	add(params, "name", profile.Name)
+	add(params, "url", profile.URL)
+	add(params, "location", profile.Location)
+	add(params, "description", profile.Description)
+
+	account := user{}
+	if err := c.request(ctx, "POST", "account/update_profile.json", params, &account); err != nil {
+		return nil, err
+	}
+	return account.to_account(), nil
+}
@@ -0,0 +1,109 @@
+// Package v1 calls the endpoints of the Twitter API v1.1 that v2 doesn't have yet,
+// like trends, geo, account settings and saved searches.
+//
+// Its Client is usually made by twigo.Client.V1, which shares the OAuth 1.0a keys,
+// the options, the retries and the rate limiter of the v2 client:
+//
+//	trends, err := client.V1().Trends(1, false)
+//
+// Tweets and users are returned as entities.Tweet and entities.User, like the v2 endpoints return them.
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+const (
+	default_base_url = "https://api.twitter.com/"
+	v1_prefix        = "1.1/"
+)
+
+// Returned when the params of a request are invalid, nothing is sent in this case.
+var ErrInvalidParams = errors.New("twigo/v1: invalid params")
+
+// Sends requests signed with OAuth 1.0a user context,
+// like the http.Client made by an oauth consumer, or the one twigo.Client.V1 passes.
+type Doer interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+// Lets a function be used as a Doer.
+type DoerFunc func(request *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+type Client struct {
+	doer    Doer
+	baseURL string
+}
+
+// Returns a client sending its requests with doer to base_url, api.twitter.com if it's empty,
+// "1.1/" is added by the client itself.
+func New(doer Doer, base_url string) *Client {
+	if base_url == "" {
+		base_url = default_base_url
+	}
+	return &Client{
+		doer:    doer,
+		baseURL: strings.TrimSuffix(base_url, "/") + "/",
+	}
+}
+
+// Sends a request to a v1.1 endpoint, like "trends/place.json", and decodes its JSON response into result,
+// params are sent in the query string, or in a form body for POST requests.
+func (c *Client) request(ctx context.Context, method, route string, params url.Values, result interface{}) error {
+	full_route := c.baseURL + v1_prefix + route
+	var body io.Reader
+	if method == "POST" {
+		body = strings.NewReader(params.Encode())
+	} else if len(params) != 0 {
+		full_route += "?" + params.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, full_route, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	response, err := c.doer.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	// twigo.Client.V1 already turns them into *twigo.APIError, other doers may not.
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		api_error := &entities.Non2XXError{Status: response.Status, StatusCode: response.StatusCode}
+		json.NewDecoder(response.Body).Decode(api_error)
+		return api_error
+	}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return fmt.Errorf("twigo/v1: decoding %s: %w", route, err)
+	}
+	return nil
+}
+
+// Adds a param to params if it's set.
+func add(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+func invalid_params(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidParams, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// A v1.1 API answering each path with its body, and recording the requests,
+// like "GET /1.1/trends/place.json?id=1" or "POST /1.1/saved_searches/create.json query=golang".
+type fake_api struct {
+	bodies   map[string]string
+	requests []string
+}
+
+func (f *fake_api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := r.Method + " " + r.URL.Path
+	if r.URL.RawQuery != "" {
+		request += "?" + r.URL.RawQuery
+	}
+	if body, _ := io.ReadAll(r.Body); len(body) != 0 {
+		request += " " + string(body)
+	}
+	f.requests = append(f.requests, request)
+
+	body, ok := f.bodies[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"code":34,"message":"Sorry, that page does not exist."}]}`))
+		return
+	}
+	w.Write([]byte(body))
+}
+
+func new_test_client(t *testing.T, bodies map[string]string) (*Client, *fake_api) {
+	fake := &fake_api{bodies: bodies}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return New(http.DefaultClient, server.URL), fake
+}
+
+func TestTrends(t *testing.T) {
+	client, fake := new_test_client(t, map[string]string{
+		"/1.1/trends/place.json": `[{
+			"trends": [{"name": "#GoLang", "url": "http://twitter.com/search?q=%23GoLang", "query": "%23GoLang", "tweet_volume": null}, {"name": "Gopher", "tweet_volume": 1200}],
+			"as_of": "2017-02-08T16:18:18Z", "created_at": "2017-02-08T16:10:33Z",
+			"locations": [{"name": "Worldwide", "woeid": 1}]
+		}]`,
+		"/1.1/trends/closest.json": `[{"name": "Tehran", "woeid": 28350859, "countryCode": "IR", "placeType": {"code": 7, "name": "Town"}}]`,
+	})
+
+	trends, err := client.Trends(1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends.Trends) != 2 || trends.Trends[0].Name != "#GoLang" || trends.Trends[0].TweetVolume != 0 || trends.Trends[1].TweetVolume != 1200 {
+		t.Errorf("trends = %+v", trends.Trends)
+	}
+	if !trends.AsOf.Equal(time.Date(2017, 2, 8, 16, 18, 18, 0, time.UTC)) || trends.Locations[0].WOEID != 1 {
+		t.Errorf("trends response = %+v", trends)
+	}
+
+	locations, err := client.ClosestTrends(35.6892, 51.389)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != 1 || locations[0].CountryCode != "IR" || locations[0].PlaceType.Name != "Town" {
+		t.Errorf("locations = %+v", locations)
+	}
+
+	want := "GET /1.1/trends/place.json?exclude=hashtags&id=1; GET /1.1/trends/closest.json?lat=35.6892&long=51.389"
+	if got := strings.Join(fake.requests, "; "); got != want {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestGeo(t *testing.T) {
+	client, fake := new_test_client(t, map[string]string{
+		"/1.1/geo/reverse_geocode.json": `{"result": {"places": [{
+			"id": "5a110d312052166f", "name": "San Francisco", "full_name": "San Francisco, CA", "place_type": "city",
+			"bounding_box": {"type": "Polygon", "coordinates": [[[-122.5, 37.7], [-122.3, 37.7], [-122.3, 37.8], [-122.5, 37.8]]]},
+			"contained_within": [{"id": "fbd6d2f5a4e4a15e", "name": "California"}]
+		}]}}`,
+		"/1.1/geo/search.json": `{"result": {"places": []}}`,
+	})
+
+	places, err := client.ReverseGeocode(37.76, -122.42, &GeoOptions{Granularity: "city", MaxResults: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(places) != 1 || places[0].PlaceType != "city" || len(places[0].ContainedWithin) != 1 {
+		t.Fatalf("places = %+v", places)
+	}
+	if box := places[0].BoundingBox; box == nil || len(box.Coordinates[0]) != 4 || box.Coordinates[0][0] != [2]float64{-122.5, 37.7} {
+		t.Errorf("bounding box = %+v", box)
+	}
+
+	if _, err := client.GeoSearch(&GeoSearchOptions{Query: "Toronto", Coordinates: &Coordinates{Latitude: 43.65, Longitude: -79.38}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ReverseGeocode(91, 0, nil); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error of a latitude out of range = %v, want %v", err, ErrInvalidParams)
+	}
+	if _, err := client.ClosestTrends(0, 181); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error of a longitude out of range = %v, want %v", err, ErrInvalidParams)
+	}
+	if _, err := client.ReverseGeocode(0, 0, &GeoOptions{Granularity: "street"}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error of an unknown granularity = %v, want %v", err, ErrInvalidParams)
+	}
+	if _, err := client.GeoSearch(&GeoSearchOptions{ContainedWithin: "fbd6d2f5a4e4a15e"}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error of a search without a query, coordinates or IP = %v, want %v", err, ErrInvalidParams)
+	}
+
+	want := "GET /1.1/geo/reverse_geocode.json?granularity=city&lat=37.76&long=-122.42&max_results=1; GET /1.1/geo/search.json?lat=43.65&long=-79.38&query=Toronto"
+	if got := strings.Join(fake.requests, "; "); got != want {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestSavedSearches(t *testing.T) {
+	search := `{"id_str": "12", "name": "golang", "query": "golang", "created_at": "Wed Oct 10 20:19:24 +0000 2018"}`
+	client, fake := new_test_client(t, map[string]string{
+		"/1.1/saved_searches/list.json":       "[" + search + "]",
+		"/1.1/saved_searches/create.json":     search,
+		"/1.1/saved_searches/show/12.json":    search,
+		"/1.1/saved_searches/destroy/12.json": search,
+	})
+
+	searches, err := client.SavedSearches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(searches) != 1 || searches[0].ID != "12" || !searches[0].CreatedAt.Equal(time.Date(2018, 10, 10, 20, 19, 24, 0, time.UTC)) {
+		t.Errorf("saved searches = %+v", searches)
+	}
+	client.CreateSavedSearch("golang")
+	client.GetSavedSearch("12")
+	if deleted, err := client.DeleteSavedSearch("12"); err != nil || deleted.Query != "golang" {
+		t.Errorf("DeleteSavedSearch = %+v, %v, want the deleted search", deleted, err)
+	}
+
+	want := "GET /1.1/saved_searches/list.json; POST /1.1/saved_searches/create.json query=golang; GET /1.1/saved_searches/show/12.json; POST /1.1/saved_searches/destroy/12.json"
+	if got := strings.Join(fake.requests, "; "); got != want {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+
+	if _, err := client.CreateSavedSearch(""); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error without a query = %v, want %v", err, ErrInvalidParams)
+	}
+	if _, err := client.DeleteSavedSearch(""); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("error without an ID = %v, want %v", err, ErrInvalidParams)
+	}
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	client, fake := new_test_client(t, map[string]string{
+		"/1.1/account/verify_credentials.json": `{
+			"id_str": "42", "name": "Bot", "screen_name": "bot", "created_at": "Wed Oct 10 20:19:24 +0000 2018",
+			"followers_count": 10, "friends_count": 3, "statuses_count": 99, "pinned_tweet_ids_str": ["7"],
+			"entities": {"url": {"urls": [{"url": "https://t.co/x", "expanded_url": "https://go.dev", "display_url": "go.dev", "indices": [0, 23]}]}, "description": {"urls": []}},
+			"email": "bot@example.com",
+			"status": {
+				"id_str": "7", "text": "hi #go", "full_text": "hi #go @gopher", "created_at": "Wed Oct 10 20:19:24 +0000 2018",
+				"favorite_count": 5, "in_reply_to_status_id_str": "6",
+				"entities": {"hashtags": [{"text": "go", "indices": [3, 6]}], "user_mentions": [{"screen_name": "gopher", "indices": [7, 14]}]}
+			}
+		}`,
+	})
+
+	account, err := client.VerifyCredentials(&VerifyCredentialsOptions{IncludeEmail: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.requests[0] != "GET /1.1/account/verify_credentials.json?include_email=true&tweet_mode=extended" {
+		t.Errorf("request = %q", fake.requests[0])
+	}
+
+	user := account.User
+	if user.ID != "42" || user.UserName != "bot" || user.PublicMetrics.FollowingCount != 3 || user.PinnedTweetID != "7" || account.Email != "bot@example.com" {
+		t.Errorf("user = %+v", user)
+	}
+	if urls := user.Entities.URL.URLs; len(urls) != 1 || urls[0].ExpandedURL != "https://go.dev" || urls[0].End != 23 {
+		t.Errorf("URLs of the user = %+v", urls)
+	}
+
+	status := account.Status
+	if status == nil || status.Text != "hi #go @gopher" || status.AuthorID != "42" || status.PublicMetrics.LikeCount != 5 {
+		t.Fatalf("status = %+v, want the full text of the latest Tweet", status)
+	}
+	if len(status.ReferencedTweets) != 1 || status.ReferencedTweets[0] != (entities.ReferencedTweet{Type: "replied_to", ID: "6"}) {
+		t.Errorf("referenced Tweets = %+v", status.ReferencedTweets)
+	}
+	if len(status.Entities.HashTags) != 1 || status.Entities.HashTags[0].Tag != "go" || len(status.Entities.Mentions) != 1 || status.Entities.Mentions[0].Tag != "gopher" {
+		t.Errorf("entities = %+v", status.Entities)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	client, fake := new_test_client(t, map[string]string{
+		"/1.1/account/update_profile.json": `{"id_str": "42", "name": "Gopher", "location": "Tehran"}`,
+	})
+
+	account, err := client.UpdateProfile(&Profile{Name: "Gopher", Location: "Tehran"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.User.Name != "Gopher" || account.Status != nil {
+		t.Errorf("account = %+v", account)
+	}
+	if want := "POST /1.1/account/update_profile.json location=Tehran&name=Gopher&skip_status=true"; fake.requests[0] != want {
+		t.Errorf("request = %q, want %q", fake.requests[0], want)
+	}
+
+	for _, profile := range []*Profile{nil, {}, {Name: strings.Repeat("a", 51)}, {Description: strings.Repeat("ب", 161)}} {
+		if _, err := client.UpdateProfile(profile); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("error of %+v = %v, want %v", profile, err, ErrInvalidParams)
+		}
+	}
+	if len(fake.requests) != 1 {
+		t.Errorf("requests = %v, want only the valid one", fake.requests)
+	}
+}
+
+func TestNon2XX(t *testing.T) {
+	client, _ := new_test_client(t, map[string]string{})
+
+	_, err := client.SavedSearches()
+	var api_error *entities.Non2XXError
+	if !errors.As(err, &api_error) || api_error.StatusCode != http.StatusNotFound || len(api_error.APIErrors) != 1 {
+		t.Errorf("error = %#v, want a 404 *entities.Non2XXError with its errors", err)
+	}
+}
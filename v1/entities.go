@@ -0,0 +1,224 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/arshamalh/twigo/entities"
+)
+
+// Layout of the times of v1.1, like "Wed Oct 10 20:19:24 +0000 2018".
+const time_layout = time.RubyDate
+
+// A Tweet as v1.1 returns it, only the fields entities.Tweet has are kept.
+type tweet struct {
+	IDStr                string `json:"id_str"`
+	Text                 string `json:"text"`
+	FullText             string `json:"full_text"`
+	CreatedAt            string `json:"created_at"`
+	Lang                 string `json:"lang"`
+	Source               string `json:"source"`
+	PossiblySensitive    bool   `json:"possibly_sensitive"`
+	InReplyToStatusIDStr string `json:"in_reply_to_status_id_str"`
+	InReplyToUserIDStr   string `json:"in_reply_to_user_id_str"`
+	QuotedStatusIDStr    string `json:"quoted_status_id_str"`
+	RetweetCount         int    `json:"retweet_count"`
+	FavoriteCount        int    `json:"favorite_count"`
+	ReplyCount           int    `json:"reply_count"`
+	QuoteCount           int    `json:"quote_count"`
+	User                 *struct {
+		IDStr string `json:"id_str"`
+	} `json:"user"`
+	RetweetedStatus *struct {
+		IDStr string `json:"id_str"`
+	} `json:"retweeted_status"`
+	Place *struct {
+		ID string `json:"id"`
+	} `json:"place"`
+	Entities struct {
+		Hashtags     []tag_entity `json:"hashtags"`
+		Symbols      []tag_entity `json:"symbols"`
+		UserMentions []struct {
+			ScreenName string `json:"screen_name"`
+			Indices    [2]int `json:"indices"`
+		} `json:"user_mentions"`
+		URLs []url_entity `json:"urls"`
+	} `json:"entities"`
+	ExtendedEntities struct {
+		Media []struct {
+			MediaKey string `json:"media_key"`
+		} `json:"media"`
+	} `json:"extended_entities"`
+}
+
+type tag_entity struct {
+	Text    string `json:"text"`
+	Indices [2]int `json:"indices"`
+}
+
+type url_entity struct {
+	URL         string `json:"url"`
+	ExpandedURL string `json:"expanded_url"`
+	DisplayURL  string `json:"display_url"`
+	Indices     [2]int `json:"indices"`
+}
+
+// A user as v1.1 returns it, only the fields entities.User has are kept, and the latest Tweet and email.
+type user struct {
+	IDStr                string   `json:"id_str"`
+	Name                 string   `json:"name"`
+	ScreenName           string   `json:"screen_name"`
+	CreatedAt            string   `json:"created_at"`
+	Description          string   `json:"description"`
+	Location             string   `json:"location"`
+	URL                  string   `json:"url"`
+	Protected            bool     `json:"protected"`
+	Verified             bool     `json:"verified"`
+	ProfileImageURLHTTPS string   `json:"profile_image_url_https"`
+	FollowersCount       int      `json:"followers_count"`
+	FriendsCount         int      `json:"friends_count"`
+	StatusesCount        int      `json:"statuses_count"`
+	ListedCount          int      `json:"listed_count"`
+	PinnedTweetIDsStr    []string `json:"pinned_tweet_ids_str"`
+	WithheldInCountries  []string `json:"withheld_in_countries"`
+	Email                string   `json:"email"`
+	Status               *tweet   `json:"status"`
+	Entities             struct {
+		URL struct {
+			URLs []url_entity `json:"urls"`
+		} `json:"url"`
+		Description struct {
+			URLs []url_entity `json:"urls"`
+		} `json:"description"`
+	} `json:"entities"`
+}
+
+func parse_time(value string) time.Time {
+	parsed, _ := time.Parse(time_layout, value)
+	return parsed
+}
+
+// Converts a v1.1 Tweet to the v2 form, fields v1.1 doesn't have are left empty.
+func (t *tweet) to_entity() entities.Tweet {
+	converted := entities.Tweet{
+		ID:                t.IDStr,
+		Text:              t.Text,
+		CreatedAt:         parse_time(t.CreatedAt),
+		InReplyToUserID:   t.InReplyToUserIDStr,
+		Lang:              t.Lang,
+		Source:            t.Source,
+		PossiblySensitive: t.PossiblySensitive,
+		PublicMetrics: entities.TweetPublicMetrics{
+			RetweetCount: t.RetweetCount,
+			ReplyCount:   t.ReplyCount,
+			LikeCount:    t.FavoriteCount,
+			QuoteCount:   t.QuoteCount,
+		},
+	}
+	if t.FullText != "" {
+		converted.Text = t.FullText
+	}
+	if t.User != nil {
+		converted.AuthorID = t.User.IDStr
+	}
+	if t.Place != nil {
+		converted.Geo.PlaceID = t.Place.ID
+	}
+
+	if t.InReplyToStatusIDStr != "" {
+		converted.ReferencedTweets = append(converted.ReferencedTweets, entities.ReferencedTweet{Type: "replied_to", ID: t.InReplyToStatusIDStr})
+	}
+	if t.QuotedStatusIDStr != "" {
+		converted.ReferencedTweets = append(converted.ReferencedTweets, entities.ReferencedTweet{Type: "quoted", ID: t.QuotedStatusIDStr})
+	}
+	if t.RetweetedStatus != nil {
+		converted.ReferencedTweets = append(converted.ReferencedTweets, entities.ReferencedTweet{Type: "retweeted", ID: t.RetweetedStatus.IDStr})
+	}
+
+	for _, hashtag := range t.Entities.Hashtags {
+		converted.Entities.HashTags = append(converted.Entities.HashTags, hashtag.to_entity())
+	}
+	for _, symbol := range t.Entities.Symbols {
+		converted.Entities.CashTags = append(converted.Entities.CashTags, symbol.to_entity())
+	}
+	for _, mention := range t.Entities.UserMentions {
+		converted.Entities.Mentions = append(converted.Entities.Mentions, entities.TweetEntityTag{
+			Start: mention.Indices[0],
+			End:   mention.Indices[1],
+			Tag:   mention.ScreenName,
+		})
+	}
+	for _, url := range t.Entities.URLs {
+		converted.Entities.URLs = append(converted.Entities.URLs, entities.URL{
+			Start:       url.Indices[0],
+			End:         url.Indices[1],
+			URL:         url.URL,
+			ExpandedURL: url.ExpandedURL,
+			DisplayURL:  url.DisplayURL,
+		})
+	}
+
+	for _, media := range t.ExtendedEntities.Media {
+		if media.MediaKey == "" {
+			continue
+		}
+		if converted.Attachments == nil {
+			converted.Attachments = map[string][]string{}
+		}
+		converted.Attachments["media_keys"] = append(converted.Attachments["media_keys"], media.MediaKey)
+	}
+	return converted
+}
+
+func (t tag_entity) to_entity() entities.TweetEntityTag {
+	return entities.TweetEntityTag{Start: t.Indices[0], End: t.Indices[1], Tag: t.Text}
+}
+
+// Converts a v1.1 user to the v2 form, fields v1.1 doesn't have are left empty.
+func (u *user) to_entity() entities.User {
+	converted := entities.User{
+		ID:              u.IDStr,
+		Name:            u.Name,
+		UserName:        u.ScreenName,
+		CreatedAt:       parse_time(u.CreatedAt),
+		Description:     u.Description,
+		Location:        u.Location,
+		ProfileImageURL: u.ProfileImageURLHTTPS,
+		Protected:       u.Protected,
+		URL:             u.URL,
+		Verified:        u.Verified,
+		PublicMetrics: entities.UserPublicMetrics{
+			FollowersCount: u.FollowersCount,
+			FollowingCount: u.FriendsCount,
+			TweetCount:     u.StatusesCount,
+			ListedCount:    u.ListedCount,
+		},
+		Withheld: entities.UserWithheld{CountryCodes: u.WithheldInCountries},
+	}
+	if len(u.PinnedTweetIDsStr) != 0 {
+		converted.PinnedTweetID = u.PinnedTweetIDsStr[0]
+	}
+
+	// The URLs of entities.User are anonymous structs, so they are filled from their JSON form.
+	fill_urls(u.Entities.URL.URLs, &converted.Entities.URL.URLs)
+	fill_urls(u.Entities.Description.URLs, &converted.Entities.Description.URLs)
+	return converted
+}
+
+func fill_urls(urls []url_entity, target interface{}) {
+	if len(urls) == 0 {
+		return
+	}
+	converted := make([]map[string]interface{}, 0, len(urls))
+	for _, url := range urls {
+		converted = append(converted, map[string]interface{}{
+			"start":        url.Indices[0],
+			"end":          url.Indices[1],
+			"url":          url.URL,
+			"expanded_url": url.ExpandedURL,
+			"display_url":  url.DisplayURL,
+		})
+	}
+	data, _ := json.Marshal(converted)
+	json.Unmarshal(data, target)
+}
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/arshamalh/twigo/utils"
+)
+
+type Place struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	Country     string `json:"country"`
+	CountryCode string `json:"country_code"`
+	// Like "poi", "neighborhood", "city", "admin" or "country".
+	PlaceType       string            `json:"place_type"`
+	URL             string            `json:"url"`
+	ContainedWithin []Place           `json:"contained_within"`
+	BoundingBox     *BoundingBox      `json:"bounding_box"`
+	Centroid        []float64         `json:"centroid"`
+	Attributes      map[string]string `json:"attributes"`
+}
+
+type BoundingBox struct {
+	Type string `json:"type"`
+	// Longitude and latitude pairs of the polygons.
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Options of ReverseGeocode and GeoSearch, they can all be left empty.
+type GeoOptions struct {
+	// Radius around the coordinates, in meters, or in feet with a "ft" suffix, like "5ft".
+	Accuracy string
+	// One of "neighborhood", "city", "admin" and "country", the smallest kind of places to return.
+	Granularity string
+	MaxResults  int
+}
+
+// Options of GeoSearch, one of Query, Coordinates and IP is required.
+type GeoSearchOptions struct {
+	GeoOptions
+	// Free-form text, like "Toronto".
+	Query       string
+	Coordinates *Coordinates
+	IP          string
+	// ID of a place the results must be in.
+	ContainedWithin string
+}
+
+func (o *GeoOptions) add_to(params url.Values) error {
+	if o == nil {
+		return nil
+	}
+	if o.Granularity != "" && !utils.Contains([]string{"neighborhood", "city", "admin", "country"}, o.Granularity) {
+		return invalid_params("granularity must be 'neighborhood', 'city', 'admin' or 'country'")
+	}
+	if o.MaxResults < 0 {
+		return invalid_params("max_results can't be negative")
+	}
+	add(params, "accuracy", o.Accuracy)
+	add(params, "granularity", o.Granularity)
+	if o.MaxResults != 0 {
+		params.Set("max_results", strconv.Itoa(o.MaxResults))
+	}
+	return nil
+}
+
+func check_coordinates(latitude, longitude float64) error {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return invalid_params("latitude must be between -90 and 90, and longitude between -180 and 180")
+	}
+	return nil
+}
+
+func add_coordinates(params url.Values, latitude, longitude float64) error {
+	if err := check_coordinates(latitude, longitude); err != nil {
+		return err
+	}
+	params.Set("lat", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Set("long", strconv.FormatFloat(longitude, 'f', -1, 64))
+	return nil
+}
+
+type geo_response struct {
+	Result struct {
+		Places []Place `json:"places"`
+	} `json:"result"`
+}
+
+// Returns places matching a text, a coordinate or an IP address,
+// their IDs can be used as the place_id of Tweets and search queries.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/geo/places-near-location/api-reference/get-geo-search
+func (c *Client) GeoSearch(options *GeoSearchOptions) ([]Place, error) {
+	return c.GeoSearchCtx(context.Background(), options)
+}
+
+// Same as GeoSearch, but the request is bound to ctx.
+func (c *Client) GeoSearchCtx(ctx context.Context, options *GeoSearchOptions) ([]Place, error) {
+	if options == nil || (options.Query == "" && options.Coordinates == nil && options.IP == "") {
+		return nil, invalid_params("one of query, coordinates and ip is required")
+	}
+
+	params := url.Values{}
+	add(params, "query", options.Query)
+	add(params, "ip", options.IP)
+	add(params, "contained_within", options.ContainedWithin)
+	if options.Coordinates != nil {
+		if err := add_coordinates(params, options.Coordinates.Latitude, options.Coordinates.Longitude); err != nil {
+			return nil, err
+		}
+	}
+	if err := options.GeoOptions.add_to(params); err != nil {
+		return nil, err
+	}
+
+	response := geo_response{}
+	if err := c.request(ctx, "GET", "geo/search.json", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Result.Places, nil
+}
+
+// Returns the places containing a coordinate, the smallest ones first.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/geo/places-near-location/api-reference/get-geo-reverse_geocode
+func (c *Client) ReverseGeocode(latitude, longitude float64, options *GeoOptions) ([]Place, error) {
+	return c.ReverseGeocodeCtx(context.Background(), latitude, longitude, options)
+}
+
+// Same as ReverseGeocode, but the request is bound to ctx.
+func (c *Client) ReverseGeocodeCtx(ctx context.Context, latitude, longitude float64, options *GeoOptions) ([]Place, error) {
+	params := url.Values{}
+	if err := add_coordinates(params, latitude, longitude); err != nil {
+		return nil, err
+	}
+	if err := options.add_to(params); err != nil {
+		return nil, err
+	}
+
+	response := geo_response{}
+	if err := c.request(ctx, "GET", "geo/reverse_geocode.json", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Result.Places, nil
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// A search query saved by the authenticated user.
+type SavedSearch struct {
+	ID        string
+	Name      string
+	Query     string
+	CreatedAt time.Time
+}
+
+type saved_search struct {
+	IDStr     string `json:"id_str"`
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	CreatedAt string `json:"created_at"`
+}
+
+func (s *saved_search) to_saved_search() *SavedSearch {
+	return &SavedSearch{
+		ID:        s.IDStr,
+		Name:      s.Name,
+		Query:     s.Query,
+		CreatedAt: parse_time(s.CreatedAt),
+	}
+}
+
+// Returns the saved searches of the authenticated user.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/manage-account-settings/api-reference/get-saved_searches-list
+func (c *Client) SavedSearches() ([]SavedSearch, error) {
+	return c.SavedSearchesCtx(context.Background())
+}
+
+// Same as SavedSearches, but the request is bound to ctx.
+func (c *Client) SavedSearchesCtx(ctx context.Context) ([]SavedSearch, error) {
+	raw_searches := []saved_search{}
+	if err := c.request(ctx, "GET", "saved_searches/list.json", nil, &raw_searches); err != nil {
+		return nil, err
+	}
+
+	searches := make([]SavedSearch, 0, len(raw_searches))
+	for _, search := range raw_searches {
+		searches = append(searches, *search.to_saved_search())
+	}
+	return searches, nil
+}
+
+// Returns a saved search of the authenticated user.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/manage-account-settings/api-reference/get-saved_searches-show-id
+func (c *Client) GetSavedSearch(id string) (*SavedSearch, error) {
+	return c.GetSavedSearchCtx(context.Background(), id)
+}
+
+// Same as GetSavedSearch, but the request is bound to ctx.
+func (c *Client) GetSavedSearchCtx(ctx context.Context, id string) (*SavedSearch, error) {
+	return c.saved_search_request(ctx, "GET", "saved_searches/show/", id)
+}
+
+// Saves a search query for the authenticated user, who can have up to 25 of them.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/manage-account-settings/api-reference/post-saved_searches-create
+func (c *Client) CreateSavedSearch(query string) (*SavedSearch, error) {
+	return c.CreateSavedSearchCtx(context.Background(), query)
+}
+
+// Same as CreateSavedSearch, but the request is bound to ctx.
+func (c *Client) CreateSavedSearchCtx(ctx context.Context, query string) (*SavedSearch, error) {
+	if query == "" {
+		return nil, invalid_params("query is required")
+	}
+
+	search := saved_search{}
+	if err := c.request(ctx, "POST", "saved_searches/create.json", url.Values{"query": {query}}, &search); err != nil {
+		return nil, err
+	}
+	return search.to_saved_search(), nil
+}
+
+// Deletes a saved search of the authenticated user, and returns it.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/accounts-and-users/manage-account-settings/api-reference/post-saved_searches-destroy-id
+func (c *Client) DeleteSavedSearch(id string) (*SavedSearch, error) {
+	return c.DeleteSavedSearchCtx(context.Background(), id)
+}
+
+// Same as DeleteSavedSearch, but the request is bound to ctx.
+func (c *Client) DeleteSavedSearchCtx(ctx context.Context, id string) (*SavedSearch, error) {
+	return c.saved_search_request(ctx, "POST", "saved_searches/destroy/", id)
+}
+
+func (c *Client) saved_search_request(ctx context.Context, method, route, id string) (*SavedSearch, error) {
+	if id == "" {
+		return nil, invalid_params("id is required")
+	}
+
+	search := saved_search{}
+	if err := c.request(ctx, method, route+url.PathEscape(id)+".json", nil, &search); err != nil {
+		return nil, err
+	}
+	return search.to_saved_search(), nil
+}
@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type Trend struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Query string `json:"query"`
+	// Tweets of the last 24 hours, zero if Twitter doesn't tell it.
+	TweetVolume int `json:"tweet_volume"`
+}
+
+// A place Twitter has trends for, WOEID is its Yahoo! Where On Earth ID.
+type Location struct {
+	Name        string `json:"name"`
+	WOEID       int64  `json:"woeid"`
+	Country     string `json:"country"`
+	CountryCode string `json:"countryCode"`
+	ParentID    int64  `json:"parentid"`
+	PlaceType   struct {
+		Code int    `json:"code"`
+		Name string `json:"name"`
+	} `json:"placeType"`
+}
+
+type TrendsResponse struct {
+	Trends    []Trend    `json:"trends"`
+	AsOf      time.Time  `json:"as_of"`
+	CreatedAt time.Time  `json:"created_at"`
+	Locations []Location `json:"locations"`
+}
+
+// Returns the top 50 trends of a place, woeid is 1 for worldwide trends,
+// use AvailableTrends or ClosestTrends to find the others.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/trends/trends-for-location/api-reference/get-trends-place
+func (c *Client) Trends(woeid int64, exclude_hashtags bool) (*TrendsResponse, error) {
+	return c.TrendsCtx(context.Background(), woeid, exclude_hashtags)
+}
+
+// Same as Trends, but the request is bound to ctx.
+func (c *Client) TrendsCtx(ctx context.Context, woeid int64, exclude_hashtags bool) (*TrendsResponse, error) {
+	params := url.Values{"id": {strconv.FormatInt(woeid, 10)}}
+	if exclude_hashtags {
+		params.Set("exclude", "hashtags")
+	}
+
+	// The response is a list with a single item.
+	trends := []TrendsResponse{}
+	if err := c.request(ctx, "GET", "trends/place.json", params, &trends); err != nil {
+		return nil, err
+	}
+	if len(trends) == 0 {
+		return &TrendsResponse{}, nil
+	}
+	return &trends[0], nil
+}
+
+// Returns the places Twitter has trends for.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/trends/locations-with-trending-topics/api-reference/get-trends-available
+func (c *Client) AvailableTrends() ([]Location, error) {
+	return c.AvailableTrendsCtx(context.Background())
+}
+
+// Same as AvailableTrends, but the request is bound to ctx.
+func (c *Client) AvailableTrendsCtx(ctx context.Context) ([]Location, error) {
+	locations := []Location{}
+	if err := c.request(ctx, "GET", "trends/available.json", nil, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// Returns the places Twitter has trends for, closest to a coordinate.
+//
+// https://developer.twitter.com/en/docs/twitter-api/v1/trends/locations-with-trending-topics/api-reference/get-trends-closest
+func (c *Client) ClosestTrends(latitude, longitude float64) ([]Location, error) {
+	return c.ClosestTrendsCtx(context.Background(), latitude, longitude)
+}
+
+// Same as ClosestTrends, but the request is bound to ctx.
+func (c *Client) ClosestTrendsCtx(ctx context.Context, latitude, longitude float64) ([]Location, error) {
+	params := url.Values{}
+	if err := add_coordinates(params, latitude, longitude); err != nil {
+		return nil, err
+	}
+
+	locations := []Location{}
+	if err := c.request(ctx, "GET", "trends/closest.json", params, &locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
@@ -0,0 +1,50 @@
+package twigo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestV1(t *testing.T) {
+	signed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signed = oauth1_signature_valid(r, "secret", "secret") && oauth1_header_params(r)["oauth_token"] == "42-token"
+		switch r.URL.Path {
+		case "/1.1/trends/place.json":
+			w.Write([]byte(`[{"trends":[{"name":"#GoLang","tweet_volume":1200}],"locations":[{"name":"Worldwide","woeid":1}]}]`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"code":89,"message":"Invalid or expired token."}]}`))
+		}
+	}))
+	defer server.Close()
+	client, _ := NewClient(
+		&Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret"},
+		WithBaseURL(server.URL),
+	)
+
+	trends, err := client.V1().Trends(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends.Trends) != 1 || trends.Trends[0].TweetVolume != 1200 {
+		t.Errorf("trends = %+v", trends.Trends)
+	}
+	if !signed {
+		t.Error("the request isn't signed with the OAuth 1.0a keys of the client")
+	}
+
+	// Errors are *APIError, like the ones of v2.
+	_, err = client.V1().VerifyCredentials(nil)
+	var api_error *APIError
+	if !errors.As(err, &api_error) || !errors.Is(err, ErrUnauthorized) || len(api_error.Codes()) != 1 {
+		t.Errorf("error = %v, want an *APIError matching %v", err, ErrUnauthorized)
+	}
+
+	app, _ := NewBearerOnlyClient("token", WithBaseURL(server.URL))
+	if _, err := app.V1().Trends(1, false); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("error of an app-only client = %v, want %v", err, ErrNoCredentials)
+	}
+}
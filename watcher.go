@@ -0,0 +1,277 @@
+package twigo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/arshamalh/twigo/entities"
+	"github.com/arshamalh/twigo/utils"
+)
+
+// A Tweet emitted by a TimelineWatcher, with the includes of the page it came in.
+type TimelineTweet struct {
+	Data     entities.Tweet
+	Includes IncludesEntity
+}
+
+// Returns the Tweet joined with its author, media, poll, place and referenced Tweets.
+func (t *TimelineTweet) Hydrate() HydratedTweet {
+	return t.Includes.Hydrate(t.Data)[0]
+}
+
+// TimelineWatcher polls a timeline and emits its new Tweets, the oldest first.
+//
+// Every poll asks for the Tweets newer than the newest one it has seen, the since_id,
+// and walks back through all their pages, so the Tweets posted while the watcher was stopped are not missed.
+// With a CheckpointFile, the since_id is saved after every emitted Tweet,
+// so a restarted watcher continues where it stopped, and never emits a Tweet twice.
+//
+// Set the exported fields before calling Run.
+type TimelineWatcher struct {
+	// Time between two polls, default is 1 minute,
+	// keep it above the rate limit of the timeline divided by its 15 minutes window.
+	Interval time.Duration
+	// Params of the requests, like "expansions" or "tweet.fields", "max_results" is 100 by default,
+	// since_id, until_id and pagination_token are set by the watcher.
+	Params Map
+	// Emits Tweets newer than this one only, it's ignored if CheckpointFile has a since_id.
+	SinceID string
+	// Without a since_id, the first poll emits the latest page of the timeline,
+	// with SkipExisting it only remembers its newest Tweet, so only Tweets posted after it are emitted.
+	SkipExisting bool
+	// Maximum number of pages of a poll, zero means all of them,
+	// a longer gap is filled by the next polls, starting from its oldest Tweets.
+	MaxPages int
+	// Path of the file to save the since_id in, nothing is saved if it's empty.
+	CheckpointFile string
+
+	// Name of the timeline, to recognize its checkpoint file.
+	timeline string
+	fetch    func(ctx context.Context, params Map) (*TweetsResponse, error)
+	// The UntilIDs of the checkpoint, when there is no CheckpointFile.
+	until_ids []string
+}
+
+type timeline_checkpoint struct {
+	Timeline string `json:"timeline"`
+	SinceID  string `json:"since_id"`
+	// Upper bounds of the parts of a gap which are not walked yet, the oldest last.
+	UntilIDs []string `json:"until_ids,omitempty"`
+}
+
+// Returns a TimelineWatcher of the home timeline of the authenticated user.
+func (c *Client) WatchHomeTimeline() *TimelineWatcher {
+	return &TimelineWatcher{
+		timeline: "home",
+		fetch: func(ctx context.Context, params Map) (*TweetsResponse, error) {
+			return c.GetHomeTimelineCtx(ctx, params)
+		},
+	}
+}
+
+// Returns a TimelineWatcher of the Tweets of a user.
+func (c *Client) WatchUserTweets(user_id string) *TimelineWatcher {
+	return &TimelineWatcher{
+		timeline: "tweets " + user_id,
+		fetch: func(ctx context.Context, params Map) (*TweetsResponse, error) {
+			return c.GetUserTweetsCtx(ctx, user_id, params)
+		},
+	}
+}
+
+// Returns a TimelineWatcher of the Tweets mentioning a user.
+func (c *Client) WatchUserMentions(user_id string) *TimelineWatcher {
+	return &TimelineWatcher{
+		timeline: "mentions " + user_id,
+		fetch: func(ctx context.Context, params Map) (*TweetsResponse, error) {
+			return c.GetUserMentionsCtx(ctx, user_id, params)
+		},
+	}
+}
+
+func (w *TimelineWatcher) set_defaults() {
+	if w.Interval <= 0 {
+		w.Interval = time.Minute
+	}
+}
+
+// Polls the timeline every Interval, and calls handler for every new Tweet, the oldest first, until ctx is done.
+//
+// It returns nil when ctx is done, or the first error of a poll or of handler,
+// the Tweet handler failed on is emitted again by the next Run, use WithRetry to survive temporary errors.
+func (w *TimelineWatcher) Run(ctx context.Context, handler func(tweet *TimelineTweet) error) error {
+	w.set_defaults()
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(ctx, handler); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Polls the timeline once, and calls handler for every new Tweet, the oldest first,
+// handy when polls are scheduled by something else, like a cron job.
+func (w *TimelineWatcher) Poll(ctx context.Context, handler func(tweet *TimelineTweet) error) error {
+	checkpoint, err := w.load_checkpoint()
+	if err != nil {
+		return err
+	}
+
+	params := make(Map, len(w.Params)+2)
+	for key, value := range w.Params {
+		params[key] = value
+	}
+	delete(params, "pagination_token")
+	delete(params, "until_id")
+	if params["max_results"] == nil {
+		params["max_results"] = 100
+	}
+	if checkpoint.SinceID != "" {
+		params["since_id"] = checkpoint.SinceID
+	}
+	if n := len(checkpoint.UntilIDs); n != 0 {
+		params["until_id"] = checkpoint.UntilIDs[n-1]
+	}
+
+	walk, err := w.collect(ctx, params, checkpoint.SinceID == "")
+	if err != nil {
+		return err
+	}
+	if walk.truncated {
+		// The older Tweets of the gap must be emitted first, so the next poll walks the gap before the fetched Tweets,
+		// they are fetched again after it.
+		checkpoint.UntilIDs = append(checkpoint.UntilIDs, walk.oldest_id)
+		return w.save_checkpoint(checkpoint)
+	}
+
+	tweets := walk.tweets
+	if checkpoint.SinceID == "" && w.SkipExisting {
+		tweets = nil
+	}
+	for _, tweet := range tweets {
+		if err := handler(tweet); err != nil {
+			return err
+		}
+		checkpoint.SinceID = tweet.Data.ID
+		if err := w.save_checkpoint(checkpoint); err != nil {
+			return err
+		}
+	}
+
+	// The part of the gap is filled, the next poll continues with the Tweets after it.
+	if n := len(checkpoint.UntilIDs); n != 0 {
+		checkpoint.UntilIDs = checkpoint.UntilIDs[:n-1]
+	}
+	// Tweets may be filtered out of the pages, the newest ID of the timeline is the start of the next poll anyway.
+	if utils.NewerID(walk.newest_id, checkpoint.SinceID) {
+		checkpoint.SinceID = walk.newest_id
+	}
+	return w.save_checkpoint(checkpoint)
+}
+
+// The pages fetched by a poll.
+type timeline_walk struct {
+	// Tweets of the pages, the oldest first, without duplicates.
+	tweets []*TimelineTweet
+	// Newest ID of the timeline, and oldest ID of the pages.
+	newest_id string
+	oldest_id string
+	// MaxPages stopped the walk before the last page.
+	truncated bool
+}
+
+// Fetches the pages of a poll, only the first page is fetched if first_page_only is true.
+func (w *TimelineWatcher) collect(ctx context.Context, params Map, first_page_only bool) (*timeline_walk, error) {
+	page, err := w.fetch(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	since_id, _ := params["since_id"].(string)
+	walk := &timeline_walk{newest_id: page.Meta.NewestID}
+	seen := map[string]bool{}
+	for pages := 1; ; pages++ {
+		for _, tweet := range page.Data {
+			// Pages shift when new Tweets come in while walking them, so some Tweets are repeated.
+			if seen[tweet.ID] || (since_id != "" && !utils.NewerID(tweet.ID, since_id)) {
+				continue
+			}
+			seen[tweet.ID] = true
+			walk.tweets = append(walk.tweets, &TimelineTweet{Data: tweet, Includes: page.Includes})
+		}
+
+		if first_page_only || page.Meta.NextToken == "" {
+			break
+		}
+		if w.MaxPages > 0 && pages >= w.MaxPages {
+			walk.truncated = len(walk.tweets) != 0
+			break
+		}
+		if page, err = page.NextPageCtx(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(walk.tweets, func(i, j int) bool {
+		return utils.NewerID(walk.tweets[j].Data.ID, walk.tweets[i].Data.ID)
+	})
+	if len(walk.tweets) != 0 {
+		walk.oldest_id = walk.tweets[0].Data.ID
+	}
+	return walk, nil
+}
+
+// Returns the checkpoint of the file, or the one in memory if there is none.
+func (w *TimelineWatcher) load_checkpoint() (*timeline_checkpoint, error) {
+	in_memory := &timeline_checkpoint{Timeline: w.timeline, SinceID: w.SinceID, UntilIDs: w.until_ids}
+	if w.CheckpointFile == "" {
+		return in_memory, nil
+	}
+
+	data, err := os.ReadFile(w.CheckpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return in_memory, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &timeline_checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("twigo: invalid checkpoint file %s: %w", w.CheckpointFile, err)
+	}
+	if checkpoint.Timeline != w.timeline {
+		return nil, fmt.Errorf("twigo: checkpoint file %s belongs to another timeline", w.CheckpointFile)
+	}
+	return checkpoint, nil
+}
+
+// Keeps the checkpoint in memory, and saves it to the checkpoint file.
+func (w *TimelineWatcher) save_checkpoint(checkpoint *timeline_checkpoint) error {
+	w.SinceID, w.until_ids = checkpoint.SinceID, checkpoint.UntilIDs
+	if w.CheckpointFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(w.CheckpointFile, data, 0o644)
+}
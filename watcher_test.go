@@ -0,0 +1,226 @@
+package twigo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arshamalh/twigo/utils"
+)
+
+// A timeline answering with 2 Tweets per page, filtered by since_id and until_id,
+// its pagination tokens are the offsets of the pages.
+type fake_timeline struct {
+	mu sync.Mutex
+	// IDs of the Tweets, the newest first.
+	ids []string
+	// since_id and until_id of the first pages of the requests, like "1000-1006", or "-" without them.
+	polls []string
+}
+
+func (f *fake_timeline) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	since_id, until_id := query.Get("since_id"), query.Get("until_id")
+	ids := []string{}
+	for _, id := range f.ids {
+		if (since_id == "" || utils.NewerID(id, since_id)) && (until_id == "" || utils.NewerID(until_id, id)) {
+			ids = append(ids, id)
+		}
+	}
+
+	start, _ := strconv.Atoi(query.Get("pagination_token"))
+	if start == 0 {
+		f.polls = append(f.polls, since_id+"-"+until_id)
+	}
+	end := start + 2
+	if end > len(ids) {
+		end = len(ids)
+	}
+	data := []Map{}
+	for _, id := range ids[start:end] {
+		data = append(data, Map{"id": id, "text": "Tweet " + id})
+	}
+	meta := Map{"result_count": len(data)}
+	if len(data) != 0 {
+		meta["newest_id"] = ids[start]
+	}
+	if end < len(ids) {
+		meta["next_token"] = strconv.Itoa(end)
+	}
+	json.NewEncoder(w).Encode(Map{"data": data, "meta": meta})
+}
+
+func (f *fake_timeline) add(ids ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ids = append(ids, f.ids...)
+}
+
+func new_test_timeline(t *testing.T, ids ...string) (*Client, *fake_timeline) {
+	timeline := &fake_timeline{ids: ids}
+	server := httptest.NewServer(timeline)
+	t.Cleanup(server.Close)
+	client, _ := NewClient(
+		&Config{ConsumerKey: "key", ConsumerSecret: "secret", AccessToken: "42-token", AccessSecret: "secret"},
+		WithBaseURL(server.URL),
+	)
+	return client, timeline
+}
+
+// Returns a handler collecting the IDs of the emitted Tweets.
+func collect_ids(ids *[]string) func(tweet *TimelineTweet) error {
+	return func(tweet *TimelineTweet) error {
+		*ids = append(*ids, tweet.Data.ID)
+		return nil
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	client, timeline := new_test_timeline(t, "105", "104", "103")
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	watcher := client.WatchHomeTimeline()
+	watcher.CheckpointFile = checkpoint
+	watcher.SkipExisting = true
+	emitted := []string{}
+	if err := watcher.Poll(context.Background(), collect_ids(&emitted)); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitted) != 0 || watcher.SinceID != "105" {
+		t.Errorf("emitted %v with since_id %s, want nothing and since_id 105", emitted, watcher.SinceID)
+	}
+
+	// A restarted watcher continues from the checkpoint, walking all pages of the gap.
+	timeline.add("1000", "999", "108", "107", "106")
+	watcher = client.WatchHomeTimeline()
+	watcher.CheckpointFile = checkpoint
+	if err := watcher.Poll(context.Background(), collect_ids(&emitted)); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(emitted) != "[106 107 108 999 1000]" {
+		t.Errorf("emitted %v, want the Tweets after 105, the oldest first", emitted)
+	}
+	if data, _ := os.ReadFile(checkpoint); !strings.Contains(string(data), `"since_id": "1000"`) {
+		t.Errorf("checkpoint = %s, want since_id 1000", data)
+	}
+
+	emitted = nil
+	if err := watcher.Poll(context.Background(), collect_ids(&emitted)); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitted) != 0 {
+		t.Errorf("emitted %v again", emitted)
+	}
+
+	mentions := client.WatchUserMentions("42")
+	mentions.CheckpointFile = checkpoint
+	if err := mentions.Poll(context.Background(), collect_ids(&emitted)); err == nil {
+		t.Error("no error for the checkpoint of another timeline")
+	}
+}
+
+func TestWatcherMaxPages(t *testing.T) {
+	client, timeline := new_test_timeline(t, "1007", "1006", "1005", "1004", "1003", "1002", "1001", "1000")
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	// Every poll fetches a single page, and is done by a new watcher, so the gap is carried by the checkpoint file.
+	emitted := []string{}
+	polls := []string{}
+	for i := 0; i < 7; i++ {
+		watcher := client.WatchHomeTimeline()
+		watcher.CheckpointFile = checkpoint
+		watcher.SinceID = "1000"
+		watcher.MaxPages = 1
+		if err := watcher.Poll(context.Background(), collect_ids(&emitted)); err != nil {
+			t.Fatal(err)
+		}
+		polls = append(polls, strings.Join(emitted, " "))
+	}
+
+	// The gap is split from its newest end, then filled from its oldest Tweets.
+	want_polls := []string{
+		"1000-", "1000-1006", "1000-1004", "1000-1002",
+		"1001-1004", "1003-1006", "1005-",
+	}
+	if fmt.Sprint(timeline.polls) != fmt.Sprint(want_polls) {
+		t.Errorf("polls = %v, want %v", timeline.polls, want_polls)
+	}
+	want := []string{
+		"", "", "", "1001",
+		"1001 1002 1003", "1001 1002 1003 1004 1005", "1001 1002 1003 1004 1005 1006 1007",
+	}
+	if strings.Join(polls, "; ") != strings.Join(want, "; ") {
+		t.Errorf("emitted after each poll = %q, want %q", polls, want)
+	}
+	if data, _ := os.ReadFile(checkpoint); strings.Contains(string(data), "until_ids") {
+		t.Errorf("checkpoint = %s, want the gap to be filled", data)
+	}
+}
+
+func TestWatcherHandlerError(t *testing.T) {
+	client, _ := new_test_timeline(t, "103", "102", "101", "100")
+	watcher := client.WatchUserTweets("42")
+	watcher.SinceID = "100"
+
+	emitted := []string{}
+	failure := errors.New("handler failed")
+	err := watcher.Poll(context.Background(), func(tweet *TimelineTweet) error {
+		if tweet.Data.ID == "102" {
+			return failure
+		}
+		emitted = append(emitted, tweet.Data.ID)
+		return nil
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("error = %v, want the one of the handler", err)
+	}
+
+	// The Tweet the handler failed on is emitted again.
+	if err := watcher.Poll(context.Background(), collect_ids(&emitted)); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(emitted) != "[101 102 103]" {
+		t.Errorf("emitted %v, want [101 102 103]", emitted)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	client, timeline := new_test_timeline(t, "100")
+	watcher := client.WatchUserTweets("42")
+	watcher.SkipExisting = true
+	watcher.Interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		timeline.add("102", "101")
+	}()
+	emitted := []string{}
+	err := watcher.Run(ctx, func(tweet *TimelineTweet) error {
+		emitted = append(emitted, tweet.Data.ID)
+		if len(emitted) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(emitted) != "[101 102]" || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("emitted %v, want the Tweets posted while running, [101 102]", emitted)
+	}
+}